@@ -0,0 +1,48 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command gochecks runs all analyzers of this repository in a single pass.
+// The analyzers are configured by a .gochecks.yaml or .gochecks.json file in
+// the module root, or the file passed with the -config flag.
+package main
+
+import (
+	"flag"
+
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/oncilla/gochecks/internal/config"
+	"github.com/oncilla/gochecks/internal/driver"
+	"github.com/oncilla/gochecks/kvcheck"
+	"github.com/oncilla/gochecks/logcheck"
+	"github.com/oncilla/gochecks/serrorscheck"
+)
+
+func main() {
+	set := config.NewSet(logcheck.NewAnalyzer, serrorscheck.NewAnalyzer)
+	flag.StringVar(&set.File, "config", "", "configuration file, discovered in the module "+
+		"root if empty")
+	driver.Setup = set.Load
+	driver.RuleDoc = kvcheck.RuleDoc
+	driver.Severity = set.Severity
+	multichecker.Main(driver.Prepare(set.Analyzers()...)...)
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command logcatalog lists the log messages and errors that the packages named
+// on the command line can emit. For every log and serrors call, the package,
+// function, level or constructor, constant message, keys, value types and
+// position are listed as JSON or Markdown. The output is sorted, such that
+// catalogs of different releases can be diffed.
+//
+// The calls are matched with the configuration of the gochecks command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/oncilla/gochecks/internal/catalog"
+	"github.com/oncilla/gochecks/internal/config"
+	"github.com/oncilla/gochecks/logcheck"
+	"github.com/oncilla/gochecks/serrorscheck"
+)
+
+func main() {
+	progname := filepath.Base(os.Args[0])
+	log.SetFlags(0)
+	log.SetPrefix(progname + ": ")
+
+	set := config.NewSet(logcheck.NewAnalyzer, serrorscheck.NewAnalyzer)
+	flag.StringVar(&set.File, "config", "", "configuration file, discovered in the module "+
+		"root if empty")
+	format := flag.String("format", "json", "output format: json or markdown")
+	tests := flag.Bool("test", false, "indicates whether test files should be cataloged, too")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] [packages]\n\nFlags:\n", progname)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	write, ok := map[string]func(w *os.File, entries []catalog.Entry) error{
+		"json": func(w *os.File, entries []catalog.Entry) error {
+			return catalog.WriteJSON(w, entries)
+		},
+		"markdown": func(w *os.File, entries []catalog.Entry) error {
+			return catalog.WriteMarkdown(w, entries)
+		},
+	}[*format]
+	if !ok {
+		log.Fatalf("unknown output format: %q", *format)
+	}
+
+	analyzers := set.Analyzers()
+	for _, a := range analyzers {
+		if err := a.Flags.Set("catalog", "true"); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := set.Load(); err != nil {
+		log.Fatal(err)
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Tests: *tests,
+	}, flag.Args()...)
+	if err == nil && len(pkgs) == 0 {
+		err = fmt.Errorf("%v matched no packages", flag.Args())
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	exitcode := 0
+	if packages.PrintErrors(pkgs) > 0 {
+		exitcode = 1
+	}
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graph.All()(func(act *checker.Action) bool {
+		if act.Err != nil {
+			log.Printf("%s: %v", act.Analyzer.Name, act.Err)
+			exitcode = 1
+		}
+		return true
+	})
+	wd, _ := os.Getwd()
+	if err := write(os.Stdout, catalog.Collect(graph, wd)); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(exitcode)
+}
@@ -25,9 +25,12 @@ package main
 import (
 	"golang.org/x/tools/go/analysis/singlechecker"
 
+	"github.com/oncilla/gochecks/internal/driver"
+	"github.com/oncilla/gochecks/kvcheck"
 	"github.com/oncilla/gochecks/serrorscheck"
 )
 
 func main() {
-	singlechecker.Main(serrorscheck.Analyzer)
+	driver.RuleDoc = kvcheck.RuleDoc
+	singlechecker.Main(driver.Prepare(serrorscheck.Analyzer)[0])
 }
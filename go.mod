@@ -1,5 +1,13 @@
 module github.com/oncilla/gochecks
 
-go 1.12
+go 1.22.0
 
-require golang.org/x/tools v0.0.0-20191002200745-87e6e099c6fe
+require (
+	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+)
@@ -0,0 +1,262 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package catalog builds a catalog of the log messages and errors that a
+// program can emit. The catalog is built from the calls that logcheck and
+// serrorscheck record in their package facts, if their catalog flag is set.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/oncilla/gochecks/kvcheck"
+)
+
+// Entry is a call in the catalog.
+type Entry struct {
+	// Package is the import path of the package that contains the call.
+	Package string `json:"package"`
+	// Function is the function that contains the call.
+	Function string `json:"function,omitempty"`
+	// Level is the level of log calls, e.g., "Info". It is empty if the level
+	// is not known, e.g., for calls of wrappers.
+	Level string `json:"level,omitempty"`
+	// Constructor is the name of the function that creates an error, e.g.,
+	// "New" or "WrapStr".
+	Constructor string `json:"constructor,omitempty"`
+	// Callee is the qualified name of the called function.
+	Callee string `json:"callee"`
+	// Message is the message, if it is constant.
+	Message string `json:"message,omitempty"`
+	// Keys are the constant keys of the call.
+	Keys []Key `json:"keys"`
+	// Pos is the position of the call in the form "file:line:column".
+	Pos string `json:"pos"`
+}
+
+// Key is a key of a call, and the type of its value.
+type Key struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// errorAnalyzers are the analyzers that check calls which create errors. The
+// calls of all other analyzers are log calls.
+var errorAnalyzers = map[string]bool{"serrorscheck": true}
+
+// levels are the log levels that are derived from the called function. Calls
+// that take the level as argument, e.g., slog.Log or zap Logw, have no level.
+var levels = map[string]bool{
+	"Trace": true, "Debug": true, "Info": true, "Warn": true, "Error": true, "Crit": true,
+	"DPanic": true, "Panic": true, "Fatal": true,
+}
+
+// contextOnly are the log functions that add context to a logger without
+// emitting a message. They are not part of the catalog.
+var contextOnly = map[string]bool{
+	"New": true, "With": true, "WithLazy": true, "WithValues": true, "Group": true,
+}
+
+// Collect returns the entries for the calls recorded in the package facts of
+// the root actions. Positions are relative to wd, if possible. The entries
+// are sorted by package and position.
+func Collect(graph *checker.Graph, wd string) []Entry {
+	var entries []Entry
+	seen := make(map[string]bool)
+	for _, act := range graph.Roots {
+		for _, pf := range act.AllPackageFacts() {
+			if pf.Package != act.Package.Types {
+				continue
+			}
+			calls, ok := kvcheck.Calls(pf.Fact)
+			if !ok {
+				continue
+			}
+			for _, call := range calls {
+				e, ok := newEntry(act.Analyzer.Name, act.Package.PkgPath, call, wd)
+				// Calls are recorded for both a package and its test variant.
+				if !ok || seen[e.Callee+"\x00"+e.Pos] {
+					continue
+				}
+				seen[e.Callee+"\x00"+e.Pos] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	Sort(entries)
+	return entries
+}
+
+// newEntry creates the entry for the call. Log calls that do not emit a
+// message are skipped.
+func newEntry(analyzer, pkg string, call kvcheck.Call, wd string) (Entry, bool) {
+	e := Entry{
+		Package:  pkg,
+		Function: call.Function,
+		Callee:   callee(call),
+		Message:  call.Message,
+		Keys:     []Key{},
+		Pos:      relative(wd, call.Pos),
+	}
+	if errorAnalyzers[analyzer] {
+		e.Constructor = call.Name
+	} else {
+		if contextOnly[call.Name] {
+			return Entry{}, false
+		}
+		e.Level = level(call.Name)
+	}
+	for _, k := range call.Keys {
+		e.Keys = append(e.Keys, Key{Name: k.Key, Type: k.Type})
+	}
+	return e, true
+}
+
+// level returns the level of the log function, e.g., "Info" for Info,
+// InfoContext and Infow.
+func level(name string) string {
+	name = strings.TrimSuffix(name, "Context")
+	if levels[name] {
+		return name
+	}
+	if trimmed := strings.TrimSuffix(name, "w"); levels[trimmed] {
+		return trimmed
+	}
+	return ""
+}
+
+func callee(call kvcheck.Call) string {
+	if call.Recv != "" {
+		return call.ImportPath + "." + call.Recv + "." + call.Name
+	}
+	return call.ImportPath + "." + call.Name
+}
+
+// relative returns the position relative to wd, if it is inside of wd.
+func relative(wd, pos string) string {
+	rel, err := filepath.Rel(wd, pos)
+	if err != nil || wd == "" || strings.HasPrefix(rel, "..") {
+		return pos
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Sort sorts the entries by package, file, line and column.
+func Sort(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Package != entries[j].Package {
+			return entries[i].Package < entries[j].Package
+		}
+		fi, li, ci := splitPos(entries[i].Pos)
+		fj, lj, cj := splitPos(entries[j].Pos)
+		switch {
+		case fi != fj:
+			return fi < fj
+		case li != lj:
+			return li < lj
+		}
+		return ci < cj
+	})
+}
+
+// splitPos splits a position of the form "file:line:column".
+func splitPos(pos string) (string, int, int) {
+	file, col := cutLast(pos)
+	file, line := cutLast(file)
+	return file, line, col
+}
+
+func cutLast(s string) (string, int) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0
+	}
+	return s[:i], n
+}
+
+// WriteJSON writes the entries as an indented JSON array.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(entries)
+}
+
+// WriteMarkdown writes the entries as a Markdown document with a table per
+// package.
+func WriteMarkdown(w io.Writer, entries []Entry) error {
+	var b strings.Builder
+	b.WriteString("# Catalog\n")
+	var pkg string
+	for i, e := range entries {
+		if i == 0 || e.Package != pkg {
+			pkg = e.Package
+			fmt.Fprintf(&b, "\n## %s\n\n", pkg)
+			b.WriteString("| Position | Function | Level or constructor | Message | Keys |\n")
+			b.WriteString("| --- | --- | --- | --- | --- |\n")
+		}
+		kind := e.Level
+		if e.Constructor != "" {
+			kind = e.Constructor
+		}
+		var keys []string
+		for _, k := range e.Keys {
+			if k.Type == "" {
+				keys = append(keys, code(k.Name))
+				continue
+			}
+			keys = append(keys, fmt.Sprintf("%s (%s)", code(k.Name), code(k.Type)))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", code(e.Pos), code(e.Function),
+			cell(kind), cell(e.Message), strings.Join(keys, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cell escapes the text for a table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// code formats the text as code in a table cell. Empty text is left empty.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + cell(s) + "`"
+}
@@ -0,0 +1,193 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package catalog
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/oncilla/gochecks/kvcheck"
+)
+
+func TestNewEntry(t *testing.T) {
+	call := func(recv, name string) kvcheck.Call {
+		return kvcheck.Call{
+			ImportPath: "log/slog",
+			Recv:       recv,
+			Name:       name,
+			Function:   "(*Server).Run",
+			Message:    "started",
+			Keys:       []kvcheck.KeyUse{{Key: "port", Type: "int"}, {Key: "addr"}},
+			Pos:        "/src/example/server.go:12:3",
+		}
+	}
+	tests := map[string]struct {
+		analyzer string
+		call     kvcheck.Call
+		want     Entry
+		ok       bool
+	}{
+		"level": {
+			analyzer: "logcheck",
+			call:     call("Logger", "InfoContext"),
+			want: Entry{
+				Package:  "example",
+				Function: "(*Server).Run",
+				Level:    "Info",
+				Callee:   "log/slog.Logger.InfoContext",
+				Message:  "started",
+				Keys:     []Key{{Name: "port", Type: "int"}, {Name: "addr"}},
+				Pos:      "server.go:12:3",
+			},
+			ok: true,
+		},
+		"sugared": {
+			analyzer: "logcheck",
+			call:     call("SugaredLogger", "Debugw"),
+			want: Entry{
+				Package:  "example",
+				Function: "(*Server).Run",
+				Level:    "Debug",
+				Callee:   "log/slog.SugaredLogger.Debugw",
+				Message:  "started",
+				Keys:     []Key{{Name: "port", Type: "int"}, {Name: "addr"}},
+				Pos:      "server.go:12:3",
+			},
+			ok: true,
+		},
+		"level argument": {
+			analyzer: "logcheck",
+			call:     call("Logger", "LogContext"),
+			want: Entry{
+				Package:  "example",
+				Function: "(*Server).Run",
+				Callee:   "log/slog.Logger.LogContext",
+				Message:  "started",
+				Keys:     []Key{{Name: "port", Type: "int"}, {Name: "addr"}},
+				Pos:      "server.go:12:3",
+			},
+			ok: true,
+		},
+		"context only": {
+			analyzer: "logcheck",
+			call:     call("Logger", "With"),
+		},
+		"constructor": {
+			analyzer: "serrorscheck",
+			call:     call("", "New"),
+			want: Entry{
+				Package:     "example",
+				Function:    "(*Server).Run",
+				Constructor: "New",
+				Callee:      "log/slog.New",
+				Message:     "started",
+				Keys:        []Key{{Name: "port", Type: "int"}, {Name: "addr"}},
+				Pos:         "server.go:12:3",
+			},
+			ok: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := newEntry(test.analyzer, "example", test.call, "/src/example")
+			if ok != test.ok {
+				t.Fatalf("ok = %t, want %t", ok, test.ok)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	entries := []Entry{
+		{Package: "b", Pos: "b.go:1:1"},
+		{Package: "a", Pos: "a.go:10:1"},
+		{Package: "a", Pos: "a.go:9:12"},
+		{Package: "a", Pos: "a.go:9:2"},
+		{Package: "a", Pos: "a.go:9:2", Callee: "errors.New"},
+	}
+	Sort(entries)
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Package+" "+e.Pos+" "+e.Callee)
+	}
+	want := []string{
+		"a a.go:9:2 ",
+		"a a.go:9:2 errors.New",
+		"a a.go:9:12 ",
+		"a a.go:10:1 ",
+		"b b.go:1:1 ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	entries := []Entry{
+		{
+			Package:  "example.com/a",
+			Function: "Run",
+			Level:    "Info",
+			Message:  "a|b",
+			Keys:     []Key{{Name: "port", Type: "int"}, {Name: "addr"}},
+			Pos:      "a.go:3:2",
+		},
+		{
+			Package:     "example.com/b",
+			Constructor: "Wrap",
+			Message:     "failed",
+			Keys:        []Key{},
+			Pos:         "b.go:7:9",
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Catalog\n" +
+		"\n## example.com/a\n\n" +
+		"| Position | Function | Level or constructor | Message | Keys |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `a.go:3:2` | `Run` | Info | a\\|b | `port` (`int`), `addr` |\n" +
+		"\n## example.com/b\n\n" +
+		"| Position | Function | Level or constructor | Message | Keys |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `b.go:7:9` |  | Wrap | failed |  |\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("got %q, want %q", got, "[]\n")
+	}
+}
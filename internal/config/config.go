@@ -0,0 +1,375 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package config loads the configuration file of the gochecks command. The
+// configuration sets the enablement and the flags of every analyzer, and can
+// be overridden for subtrees of the module:
+//
+//	analyzers:
+//	  logcheck:
+//	    importpaths: [github.com/scionproto/scion/pkg/log]
+//	    keypattern: "^[a-z][a-zA-Z0-9]*$"
+//	    reservedkeys: [t, lvl, msg]
+//	    keytype: github.com/scionproto/scion/pkg/log/logkey.Key
+//	    keyfile: tools/logkeys.txt
+//	    keyschema: tools/logschema.txt
+//	    rules:
+//	      LOG007: {enabled: false}
+//	      LOG001: {severity: error}
+//	  serrorscheck:
+//	    enabled: false
+//	overrides:
+//	  - path: go/legacy
+//	    analyzers:
+//	      logcheck:
+//	        enabled: false
+//
+// The same structure is accepted as JSON.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the names of the configuration file that are looked up in
+// the module root, in order.
+var FileNames = []string{".gochecks.yaml", ".gochecks.yml", ".gochecks.json"}
+
+// Config is the configuration of the gochecks command.
+type Config struct {
+	// Analyzers maps the analyzer names to their configuration.
+	Analyzers map[string]Analyzer `yaml:"analyzers" json:"analyzers"`
+	// Overrides are applied to the packages in the subtree of their path.
+	// Overrides with a more specific path take precedence.
+	Overrides []Override `yaml:"overrides" json:"overrides"`
+
+	// dir is the directory that the override paths are relative to.
+	dir string
+}
+
+// Override is the configuration for a subtree.
+type Override struct {
+	// Path is the root of the subtree, relative to the configuration file.
+	Path      string              `yaml:"path" json:"path"`
+	Analyzers map[string]Analyzer `yaml:"analyzers" json:"analyzers"`
+}
+
+// Analyzer is the configuration of a single analyzer. Unset fields are
+// inherited from the enclosing configuration.
+type Analyzer struct {
+	// Enabled indicates whether the diagnostics of the analyzer are reported.
+	// Analyzers are enabled by default.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// ImportPaths sets the importpaths flag.
+	ImportPaths []string `yaml:"importpaths,omitempty" json:"importpaths,omitempty"`
+	// KeyPattern sets the keypattern flag.
+	KeyPattern *string `yaml:"keypattern,omitempty" json:"keypattern,omitempty"`
+	// ReservedKeys sets the reservedkeys flag.
+	ReservedKeys []string `yaml:"reservedkeys,omitempty" json:"reservedkeys,omitempty"`
+	// KeyType sets the keytype flag.
+	KeyType *string `yaml:"keytype,omitempty" json:"keytype,omitempty"`
+	// KeyFile sets the keyfile flag. Relative paths are relative to the
+	// configuration file.
+	KeyFile *string `yaml:"keyfile,omitempty" json:"keyfile,omitempty"`
+	// KeySchema sets the keyschema flag. Relative paths are relative to the
+	// configuration file.
+	KeySchema *string `yaml:"keyschema,omitempty" json:"keyschema,omitempty"`
+	// Rules maps rule IDs to their configuration. The disabled rules set the
+	// disable flag.
+	Rules map[string]Rule `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// Rule is the configuration of a single rule. Unset fields are inherited from
+// the enclosing configuration.
+type Rule struct {
+	// Enabled indicates whether the rule is reported. Rules are enabled by
+	// default.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// Severity is one of error, warning or info. The default is warning.
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// Severities are the valid rule severities.
+var Severities = []string{"error", "warning", "info"}
+
+// DefaultSeverity is the severity of rules without configured severity.
+const DefaultSeverity = "warning"
+
+// Severity returns the severity of the rule.
+func (a Analyzer) Severity(rule string) string {
+	if s := a.Rules[rule].Severity; s != "" {
+		return s
+	}
+	return DefaultSeverity
+}
+
+// IsEnabled reports whether the analyzer is enabled.
+func (a Analyzer) IsEnabled() bool {
+	return a.Enabled == nil || *a.Enabled
+}
+
+// Flags returns the flag values that are set by the configuration.
+func (a Analyzer) Flags() map[string]string {
+	flags := make(map[string]string)
+	if a.ImportPaths != nil {
+		flags["importpaths"] = strings.Join(a.ImportPaths, ",")
+	}
+	if a.KeyPattern != nil {
+		flags["keypattern"] = *a.KeyPattern
+	}
+	if a.ReservedKeys != nil {
+		flags["reservedkeys"] = strings.Join(a.ReservedKeys, ",")
+	}
+	if a.KeyType != nil {
+		flags["keytype"] = *a.KeyType
+	}
+	if a.KeyFile != nil {
+		flags["keyfile"] = *a.KeyFile
+	}
+	if a.KeySchema != nil {
+		flags["keyschema"] = *a.KeySchema
+	}
+	var disabled []string
+	for id, r := range a.Rules {
+		if r.Enabled != nil && !*r.Enabled {
+			disabled = append(disabled, id)
+		}
+	}
+	if disabled != nil {
+		sort.Strings(disabled)
+		flags["disable"] = strings.Join(disabled, ",")
+	}
+	return flags
+}
+
+// merge returns the configuration with the fields that are set in o replaced.
+func (a Analyzer) merge(o Analyzer) Analyzer {
+	if o.Enabled != nil {
+		a.Enabled = o.Enabled
+	}
+	if o.ImportPaths != nil {
+		a.ImportPaths = o.ImportPaths
+	}
+	if o.KeyPattern != nil {
+		a.KeyPattern = o.KeyPattern
+	}
+	if o.ReservedKeys != nil {
+		a.ReservedKeys = o.ReservedKeys
+	}
+	if o.KeyType != nil {
+		a.KeyType = o.KeyType
+	}
+	if o.KeyFile != nil {
+		a.KeyFile = o.KeyFile
+	}
+	if o.KeySchema != nil {
+		a.KeySchema = o.KeySchema
+	}
+	if o.Rules != nil {
+		rules := make(map[string]Rule, len(a.Rules)+len(o.Rules))
+		for id, r := range a.Rules {
+			rules[id] = r
+		}
+		for id, r := range o.Rules {
+			merged := rules[id]
+			if r.Enabled != nil {
+				merged.Enabled = r.Enabled
+			}
+			if r.Severity != "" {
+				merged.Severity = r.Severity
+			}
+			rules[id] = merged
+		}
+		a.Rules = rules
+	}
+	return a
+}
+
+// Load loads the configuration file. Files with a .json extension are parsed
+// as JSON, all others as YAML. Unknown fields are rejected.
+func Load(file string) (*Config, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if filepath.Ext(file) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		if err = dec.Decode(&cfg); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	if err := checkSeverities(cfg.Analyzers); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	for _, o := range cfg.Overrides {
+		if o.Path == "" || filepath.IsAbs(o.Path) {
+			return nil, fmt.Errorf("parsing %s: override path must be relative: %q", file,
+				o.Path)
+		}
+		if err := checkSeverities(o.Analyzers); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	cfg.dir = filepath.Dir(abs)
+	cfg.absFiles(cfg.Analyzers)
+	for _, o := range cfg.Overrides {
+		cfg.absFiles(o.Analyzers)
+	}
+	return &cfg, nil
+}
+
+// absFiles makes the relative paths of the files that are referenced by the
+// analyzer configurations absolute.
+func (c *Config) absFiles(analyzers map[string]Analyzer) {
+	abs := func(file *string) *string {
+		if file == nil || *file == "" || filepath.IsAbs(*file) {
+			return file
+		}
+		joined := filepath.Join(c.dir, filepath.FromSlash(*file))
+		return &joined
+	}
+	for name, a := range analyzers {
+		a.KeyFile = abs(a.KeyFile)
+		a.KeySchema = abs(a.KeySchema)
+		analyzers[name] = a
+	}
+}
+
+// checkSeverities checks that all configured severities are valid.
+func checkSeverities(analyzers map[string]Analyzer) error {
+	for name, a := range analyzers {
+		for id, r := range a.Rules {
+			if r.Severity != "" && !contains(Severities, r.Severity) {
+				return fmt.Errorf("%s: %s: unknown severity: %q", name, id, r.Severity)
+			}
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Discover returns the configuration file in the root of the module that
+// contains dir. The empty string is returned if there is no such file.
+func Discover(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+	for _, name := range FileNames {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// Resolve returns the configuration of the named analyzer for the packages in
+// dir. The overrides that contain dir are applied from the least to the most
+// specific path.
+func (c *Config) Resolve(name, dir string) Analyzer {
+	a := c.Analyzers[name]
+	rel, ok := c.relative(dir)
+	if !ok {
+		return a
+	}
+	var overrides []Override
+	for _, o := range c.Overrides {
+		path := filepath.Clean(filepath.FromSlash(o.Path))
+		if path == "." || rel == path || strings.HasPrefix(rel, path+string(filepath.Separator)) {
+			overrides = append(overrides, o)
+		}
+	}
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return depth(overrides[i].Path) < depth(overrides[j].Path)
+	})
+	for _, o := range overrides {
+		a = a.merge(o.Analyzers[name])
+	}
+	return a
+}
+
+// relative returns dir relative to the directory of the configuration file,
+// if dir is inside of it.
+func (c *Config) relative(dir string) (string, bool) {
+	if c.dir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(c.dir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// depth returns the number of elements in the slash-separated path.
+func depth(path string) int {
+	path = filepath.ToSlash(filepath.Clean(filepath.FromSlash(path)))
+	if path == "." {
+		return 0
+	}
+	return strings.Count(path, "/") + 1
+}
@@ -0,0 +1,322 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/oncilla/gochecks/internal/config"
+	"github.com/oncilla/gochecks/logcheck"
+	"github.com/oncilla/gochecks/serrorscheck"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, ".gochecks.yaml")
+	write(t, yamlFile, `
+analyzers:
+  logcheck:
+    importpaths: [example.com/log]
+    keypattern: ""
+overrides:
+  - path: sub/dir
+    analyzers:
+      logcheck:
+        enabled: false
+        reservedkeys: [id, seq]
+`)
+	jsonFile := filepath.Join(dir, "gochecks.json")
+	write(t, jsonFile, `{
+  "analyzers": {"logcheck": {"importpaths": ["example.com/log"], "keypattern": ""}},
+  "overrides": [
+    {"path": "sub/dir", "analyzers": {"logcheck": {"enabled": false, "reservedkeys": ["id", "seq"]}}}
+  ]
+}`)
+	for _, file := range []string{yamlFile, jsonFile} {
+		cfg, err := config.Load(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base := cfg.Resolve("logcheck", dir)
+		wantFlags := map[string]string{"importpaths": "example.com/log", "keypattern": ""}
+		if !base.IsEnabled() || !reflect.DeepEqual(base.Flags(), wantFlags) {
+			t.Errorf("%s: base: enabled=%t flags=%v", file, base.IsEnabled(), base.Flags())
+		}
+		sub := cfg.Resolve("logcheck", filepath.Join(dir, "sub", "dir", "pkg"))
+		wantFlags["reservedkeys"] = "id,seq"
+		if sub.IsEnabled() || !reflect.DeepEqual(sub.Flags(), wantFlags) {
+			t.Errorf("%s: sub: enabled=%t flags=%v", file, sub.IsEnabled(), sub.Flags())
+		}
+		sibling := cfg.Resolve("logcheck", filepath.Join(dir, "sub", "directory"))
+		if !sibling.IsEnabled() {
+			t.Errorf("%s: override applied to sibling directory", file)
+		}
+		if other := cfg.Resolve("serrorscheck", dir); !other.IsEnabled() ||
+			len(other.Flags()) != 0 {
+
+			t.Errorf("%s: unconfigured analyzer: %v", file, other)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown.yaml":  "analyzers: {logcheck: {keypatern: x}}",
+		"unknown.json":  `{"analyzers": {"logcheck": {"keypatern": "x"}}}`,
+		"absolute.yaml": "overrides: [{path: /abs}]",
+		"syntax.json":   `{"analyzers": `,
+		"severity.yaml": "analyzers: {logcheck: {rules: {LOG001: {severity: fatal}}}}",
+	}
+	dir := t.TempDir()
+	for name, content := range tests {
+		file := filepath.Join(dir, name)
+		write(t, file, content)
+		if _, err := config.Load(file); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".gochecks.yaml")
+	write(t, file, `
+analyzers:
+  logcheck:
+    rules:
+      LOG007: {enabled: false}
+      LOG001: {severity: error}
+overrides:
+  - path: sub
+    analyzers:
+      logcheck:
+        rules:
+          LOG002: {enabled: false}
+          LOG007: {enabled: true, severity: info}
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := cfg.Resolve("logcheck", dir)
+	if got := base.Flags()["disable"]; got != "LOG007" {
+		t.Errorf("base: disable=%q", got)
+	}
+	if got := base.Severity("LOG001"); got != "error" {
+		t.Errorf("base: LOG001 severity=%q", got)
+	}
+	if got := base.Severity("LOG002"); got != config.DefaultSeverity {
+		t.Errorf("base: LOG002 severity=%q", got)
+	}
+	sub := cfg.Resolve("logcheck", filepath.Join(dir, "sub"))
+	if got := sub.Flags()["disable"]; got != "LOG002" {
+		t.Errorf("sub: disable=%q", got)
+	}
+	if got := sub.Severity("LOG001") + "," + sub.Severity("LOG007"); got != "error,info" {
+		t.Errorf("sub: severities=%q", got)
+	}
+	if got := cfg.Resolve("logcheck", dir).Severity("LOG007"); got != config.DefaultSeverity {
+		t.Errorf("override modified base configuration: LOG007 severity=%q", got)
+	}
+
+	set := config.NewSet(logcheck.NewAnalyzer)
+	set.File = file
+	if err := set.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := set.Severity("logcheck", "LOG007", filepath.Join(dir, "sub", "x.go")); got != "info" {
+		t.Errorf("set: LOG007 severity=%q", got)
+	}
+	if got := set.Severity("logcheck", "LOG007", ""); got != config.DefaultSeverity {
+		t.Errorf("set: root LOG007 severity=%q", got)
+	}
+	if got := set.Severity("logcheck", "LOG001", ""); got != "error" {
+		t.Errorf("set: root LOG001 severity=%q", got)
+	}
+}
+
+func TestKeyRegistry(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".gochecks.yaml")
+	write(t, file, `
+analyzers:
+  logcheck:
+    keytype: example.com/logkey.Key
+    keyfile: tools/keys.txt
+    keyschema: tools/schema.txt
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"keytype":   "example.com/logkey.Key",
+		"keyfile":   filepath.Join(dir, "tools", "keys.txt"),
+		"keyschema": filepath.Join(dir, "tools", "schema.txt"),
+	}
+	if got := cfg.Resolve("logcheck", filepath.Join(dir, "pkg")).Flags(); !reflect.DeepEqual(got, want) {
+		t.Errorf("flags=%v, want %v", got, want)
+	}
+
+	// The key files are read when the flags are validated.
+	set := config.NewSet(logcheck.NewAnalyzer)
+	set.File = file
+	if err := set.Load(); err == nil {
+		t.Error("expected error for missing key file")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "tools"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write(t, filepath.Join(dir, "tools", "keys.txt"), "isd_as\n")
+	write(t, filepath.Join(dir, "tools", "schema.txt"), "isd_as: uint64\n")
+	set = config.NewSet(logcheck.NewAnalyzer)
+	set.File = file
+	if err := set.Load(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write(t, filepath.Join(dir, "go.mod"), "module example.com/m\n")
+	if file, err := config.Discover(sub); err != nil || file != "" {
+		t.Errorf("without file: got %q, %v", file, err)
+	}
+	want := filepath.Join(dir, ".gochecks.json")
+	write(t, want, "{}")
+	if file, err := config.Discover(sub); err != nil || file != want {
+		t.Errorf("got %q, %v, want %q", file, err, want)
+	}
+}
+
+func TestSet(t *testing.T) {
+	testdata := analysistest.TestData()
+	set := config.NewSet(logcheck.NewAnalyzer, serrorscheck.NewAnalyzer)
+	set.File = filepath.Join(testdata, "src", "configured", ".gochecks.yaml")
+	if err := set.Load(); err != nil {
+		t.Fatal(err)
+	}
+	analyzers := set.Analyzers()
+	if len(analyzers) != 2 || analyzers[0].Name != "logcheck" {
+		t.Fatalf("unexpected analyzers: %v", analyzers)
+	}
+	analysistest.Run(t, testdata, analyzers[0], "configured/...")
+}
+
+func TestDirectives(t *testing.T) {
+	testdata := analysistest.TestData()
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  testdata,
+		Env:  append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}, "directives")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("packages contain errors")
+	}
+	set := config.NewSet(logcheck.NewAnalyzer, serrorscheck.NewAnalyzer)
+	graph, err := checker.Analyze(set.Analyzers(), pkgs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, act := range graph.Roots {
+		for _, d := range act.Diagnostics {
+			if !strings.HasPrefix(d.Message, "context should be even") {
+				t.Errorf("unexpected diagnostic: %s", d.Message)
+			}
+			pos := act.Package.Fset.Position(d.Pos)
+			got = append(got, fmt.Sprintf("%s:%d", act.Analyzer.Name, pos.Line))
+		}
+	}
+	want := []string{
+		"logcheck:41",
+		"logcheck:42",
+		"serrorscheck:42",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCommandLineFlags(t *testing.T) {
+	set := config.NewSet(logcheck.NewAnalyzer, serrorscheck.NewAnalyzer)
+	set.File = filepath.Join(t.TempDir(), ".gochecks.yaml")
+	write(t, set.File, "analyzers: {logcheck: {keypattern: ''}}")
+	for _, a := range set.Analyzers() {
+		f := a.Flags.Lookup("keyuses")
+		if f == nil {
+			t.Fatalf("%s: keyuses flag not exposed", a.Name)
+		}
+		if err := f.Value.Set("true"); err != nil {
+			t.Fatal(err)
+		}
+		if f.Value.String() != "true" {
+			t.Errorf("%s: keyuses=%s", a.Name, f.Value)
+		}
+		if err := f.Value.Set("maybe"); err == nil {
+			t.Errorf("%s: expected error for invalid value", a.Name)
+		}
+	}
+	if err := set.Load(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".gochecks.yaml")
+	for _, content := range []string{
+		"analyzers: {unknown: {}}",
+		"overrides: [{path: sub, analyzers: {logcheck: {keypattern: '('}}}]",
+		"analyzers: {logcheck: {rules: {SERR001: {enabled: false}}}}",
+	} {
+		write(t, file, content)
+		set := config.NewSet(logcheck.NewAnalyzer)
+		set.File = file
+		if err := set.Load(); err == nil {
+			t.Errorf("%s: expected error", content)
+		}
+	}
+}
+
+func write(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,267 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Set runs analyzers with the configuration that applies to the analyzed
+// package. For every distinct configuration, a separate instance of the
+// analyzer is created and its flags are set accordingly.
+type Set struct {
+	// File is the configuration file. If it is empty, the file is discovered
+	// in the root of the module that contains the working directory.
+	File string
+
+	factories map[string]func() *analysis.Analyzer
+
+	once sync.Once
+	cfg  *Config
+	err  error
+
+	mu        sync.Mutex
+	instances map[string]*analysis.Analyzer
+	// flags are the values of the command line flags, per analyzer.
+	flags map[string]map[string]string
+}
+
+// CommandLineFlags are the flags of the analyzers that are not part of the
+// configuration. The analyzers of the set expose them, and their values apply
+// to all instances.
+var CommandLineFlags = []string{"keyuses", "catalog"}
+
+// NewSet creates a set of the analyzers created by the factories. Every
+// invocation of a factory must return a new instance with its own flags.
+func NewSet(factories ...func() *analysis.Analyzer) *Set {
+	s := &Set{
+		factories: make(map[string]func() *analysis.Analyzer),
+		instances: make(map[string]*analysis.Analyzer),
+		flags:     make(map[string]map[string]string),
+	}
+	for _, factory := range factories {
+		s.factories[factory().Name] = factory
+	}
+	return s
+}
+
+// Analyzers returns the analyzers of the set, sorted by name. The
+// configuration is loaded when the first package is analyzed.
+func (s *Set) Analyzers() []*analysis.Analyzer {
+	var names []string
+	for name := range s.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var analyzers []*analysis.Analyzer
+	for _, name := range names {
+		base := s.factories[name]()
+		a := &analysis.Analyzer{
+			Name:             base.Name,
+			Doc:              base.Doc,
+			URL:              base.URL,
+			Requires:         base.Requires,
+			ResultType:       base.ResultType,
+			RunDespiteErrors: base.RunDespiteErrors,
+			FactTypes:        base.FactTypes,
+			Run: func(pass *analysis.Pass) (interface{}, error) {
+				return s.run(pass, name)
+			},
+		}
+		for _, name := range CommandLineFlags {
+			if f := base.Flags.Lookup(name); f != nil {
+				a.Flags.Var(&commandLineFlag{set: s, analyzer: base.Name, base: f}, name, f.Usage)
+			}
+		}
+		analyzers = append(analyzers, a)
+	}
+	return analyzers
+}
+
+// commandLineFlag stores the value of a command line flag in the set. The
+// value is validated with the flag of a base instance.
+type commandLineFlag struct {
+	set      *Set
+	analyzer string
+	base     *flag.Flag
+}
+
+func (f *commandLineFlag) String() string {
+	if f.set == nil {
+		return ""
+	}
+	f.set.mu.Lock()
+	defer f.set.mu.Unlock()
+	if v, ok := f.set.flags[f.analyzer][f.base.Name]; ok {
+		return v
+	}
+	return f.base.DefValue
+}
+
+func (f *commandLineFlag) Set(v string) error {
+	if err := f.base.Value.Set(v); err != nil {
+		return err
+	}
+	f.set.mu.Lock()
+	defer f.set.mu.Unlock()
+	if f.set.flags[f.analyzer] == nil {
+		f.set.flags[f.analyzer] = make(map[string]string)
+	}
+	f.set.flags[f.analyzer][f.base.Name] = f.base.Value.String()
+	return nil
+}
+
+// IsBoolFlag reports whether the base flag is a boolean flag.
+func (f *commandLineFlag) IsBoolFlag() bool {
+	b, ok := f.base.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// run runs the instance of the named analyzer that is configured for the
+// package. The diagnostics of disabled analyzers are dropped, but their facts
+// are still exported for the dependent packages.
+func (s *Set) run(pass *analysis.Pass, name string) (interface{}, error) {
+	cfg, err := s.config()
+	if err != nil {
+		return nil, err
+	}
+	ac := cfg.Resolve(name, packageDir(pass))
+	a, err := s.instance(name, ac)
+	if err != nil {
+		return nil, err
+	}
+	if !ac.IsEnabled() {
+		p := *pass
+		p.Report = func(analysis.Diagnostic) {}
+		pass = &p
+	}
+	return a.Run(pass)
+}
+
+// Load loads the configuration. It is called implicitly when the first
+// package is analyzed, but can be called earlier to surface errors once.
+func (s *Set) Load() error {
+	_, err := s.config()
+	return err
+}
+
+// config loads the configuration once.
+func (s *Set) config() (*Config, error) {
+	s.once.Do(func() {
+		file := s.File
+		if file == "" {
+			if file, s.err = Discover("."); s.err != nil || file == "" {
+				s.cfg = &Config{}
+				return
+			}
+		}
+		if s.cfg, s.err = Load(file); s.err != nil {
+			return
+		}
+		s.err = s.validate(file)
+	})
+	return s.cfg, s.err
+}
+
+// validate checks that the configuration only mentions known analyzers, and
+// that the flag values are accepted.
+func (s *Set) validate(file string) error {
+	check := func(analyzers map[string]Analyzer) error {
+		for name, ac := range analyzers {
+			if _, ok := s.factories[name]; !ok {
+				return fmt.Errorf("%s: unknown analyzer: %q", file, name)
+			}
+			if _, err := s.instance(name, ac); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+		}
+		return nil
+	}
+	if err := check(s.cfg.Analyzers); err != nil {
+		return err
+	}
+	for _, o := range s.cfg.Overrides {
+		if err := check(o.Analyzers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// instance returns the instance of the named analyzer with the flags of the
+// configuration and the command line.
+func (s *Set) instance(name string, ac Analyzer) (*analysis.Analyzer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flags := ac.Flags()
+	for f, value := range s.flags[name] {
+		flags[f] = value
+	}
+	var entries []string
+	for f, value := range flags {
+		entries = append(entries, f+"="+value)
+	}
+	sort.Strings(entries)
+	key := name + "\x00" + strings.Join(entries, "\x00")
+	if a, ok := s.instances[key]; ok {
+		return a, nil
+	}
+	a := s.factories[name]()
+	for f, value := range flags {
+		if err := a.Flags.Set(f, value); err != nil {
+			return nil, fmt.Errorf("configuring %s: -%s: %w", name, f, err)
+		}
+	}
+	s.instances[key] = a
+	return a, nil
+}
+
+// Severity returns the configured severity of the rule for the file. For an
+// empty file, it returns the severity of the root configuration, without any
+// overrides. It returns the default severity if the configuration cannot be
+// loaded.
+func (s *Set) Severity(analyzer, rule, file string) string {
+	cfg, err := s.config()
+	if err != nil {
+		return DefaultSeverity
+	}
+	if file == "" {
+		return cfg.Analyzers[analyzer].Severity(rule)
+	}
+	return cfg.Resolve(analyzer, filepath.Dir(file)).Severity(rule)
+}
+
+// packageDir returns the directory of the first file of the package.
+func packageDir(pass *analysis.Pass) string {
+	if len(pass.Files) == 0 {
+		return ""
+	}
+	return filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+}
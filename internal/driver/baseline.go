@@ -0,0 +1,361 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// finding identifies a diagnostic independent of its line number, such that
+// it is stable under unrelated changes to the file.
+type finding struct {
+	Analyzer string `json:"analyzer"`
+	// File is the path of the file relative to the module root.
+	File string `json:"file"`
+	// Expr is the source text of the diagnostic range with normalized white
+	// space.
+	Expr    string `json:"expr"`
+	Message string `json:"message"`
+}
+
+// entry is a finding in the baseline with the number of its occurrences.
+type entry struct {
+	finding
+	Count int `json:"count"`
+}
+
+// baseline is the set of known findings.
+type baseline struct {
+	Entries []entry `json:"entries"`
+}
+
+// newBaseline creates a baseline that contains the findings.
+func newBaseline(findings []finding) *baseline {
+	counts := make(map[finding]int)
+	for _, f := range findings {
+		counts[f]++
+	}
+	return &baseline{Entries: sortedEntries(counts)}
+}
+
+// readBaseline reads the baseline from the file.
+func readBaseline(file string) (*baseline, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var b baseline
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", file, err)
+	}
+	return &b, nil
+}
+
+// writeBaseline writes the baseline to the file.
+func writeBaseline(file string, b *baseline) error {
+	raw, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(raw, '\n'), 0644)
+}
+
+// known reports for every finding whether it is covered by the baseline. A
+// baseline entry covers as many findings as it has occurrences.
+func (b *baseline) known(findings []finding) []bool {
+	counts := b.counts()
+	known := make([]bool, len(findings))
+	for i, f := range findings {
+		if counts[f] > 0 {
+			counts[f]--
+			known[i] = true
+		}
+	}
+	return known
+}
+
+// fixed returns the baseline entries that are no longer found. The count of
+// the returned entries is the number of occurrences that were not found.
+func (b *baseline) fixed(findings []finding) []entry {
+	counts := b.counts()
+	for _, f := range findings {
+		if counts[f] > 0 {
+			counts[f]--
+		}
+	}
+	return sortedEntries(counts)
+}
+
+func (b *baseline) counts() map[finding]int {
+	counts := make(map[finding]int)
+	for _, e := range b.Entries {
+		counts[e.finding] += e.Count
+	}
+	return counts
+}
+
+// sortedEntries returns the entries with a positive count sorted by file,
+// analyzer, message and expression.
+func sortedEntries(counts map[finding]int) []entry {
+	entries := []entry{}
+	for f, count := range counts {
+		if count > 0 {
+			entries = append(entries, entry{finding: f, Count: count})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+		if a.Message != b.Message {
+			return a.Message < b.Message
+		}
+		return a.Expr < b.Expr
+	})
+	return entries
+}
+
+// diagKey identifies a diagnostic by position. Files that belong to multiple
+// packages, e.g., foo and foo.test, report the same diagnostic twice.
+type diagKey struct {
+	pos, end token.Position
+	analyzer string
+	message  string
+}
+
+// collectFindings returns the findings of the root actions. Duplicate
+// diagnostics are only returned once.
+func collectFindings(graph *checker.Graph) ([]finding, []diagKey) {
+	src := newSources()
+	var findings []finding
+	var keys []diagKey
+	seen := make(map[diagKey]bool)
+	for _, act := range graph.Roots {
+		fset := act.Package.Fset
+		for _, diag := range act.Diagnostics {
+			key := newDiagKey(fset, act.Analyzer, diag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, src.finding(fset, act.Analyzer, diag))
+			keys = append(keys, key)
+		}
+	}
+	return findings, keys
+}
+
+func newDiagKey(fset *token.FileSet, a *analysis.Analyzer, diag analysis.Diagnostic) diagKey {
+	return diagKey{
+		pos:      fset.Position(diag.Pos),
+		end:      fset.Position(diag.End),
+		analyzer: a.Name,
+		message:  diag.Message,
+	}
+}
+
+// filterKnown removes the diagnostics of the root actions that are covered by
+// the baseline.
+func filterKnown(graph *checker.Graph, b *baseline) {
+	findings, keys := collectFindings(graph)
+	drop := make(map[diagKey]bool)
+	for i, known := range b.known(findings) {
+		drop[keys[i]] = known
+	}
+	for _, act := range graph.Roots {
+		var diags []analysis.Diagnostic
+		for _, diag := range act.Diagnostics {
+			if !drop[newDiagKey(act.Package.Fset, act.Analyzer, diag)] {
+				diags = append(diags, diag)
+			}
+		}
+		act.Diagnostics = diags
+	}
+}
+
+// baselineFilter drops the reported diagnostics that are covered by the
+// baseline. It is shared by all analyzers and packages of a run.
+type baselineFilter struct {
+	once sync.Once
+	err  error
+
+	mu     sync.Mutex
+	counts map[finding]int
+	src    sources
+	// known records the decisions per diagnostic, such that a diagnostic
+	// that is reported for a package and its test variant is only counted
+	// once.
+	known map[diagKey]bool
+}
+
+// withBaseline wraps the analyzers such that the diagnostics that are covered
+// by the baseline file are dropped when they are reported. The baseline is
+// read when the first package is analyzed.
+func withBaseline(analyzers []*analysis.Analyzer) []*analysis.Analyzer {
+	f := &baselineFilter{}
+	var wrapped []*analysis.Analyzer
+	for _, a := range analyzers {
+		a := a
+		w := *a
+		w.Run = func(pass *analysis.Pass) (interface{}, error) {
+			return f.run(pass, a)
+		}
+		wrapped = append(wrapped, &w)
+	}
+	return wrapped
+}
+
+// run runs the analyzer with a report function that consults the baseline.
+func (f *baselineFilter) run(pass *analysis.Pass, a *analysis.Analyzer) (interface{}, error) {
+	f.once.Do(func() {
+		if baselineFile == "" {
+			return
+		}
+		var b *baseline
+		if b, f.err = readBaseline(baselineFile); f.err != nil {
+			return
+		}
+		f.counts = b.counts()
+		f.src = newSources()
+		f.known = make(map[diagKey]bool)
+	})
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.counts == nil {
+		return a.Run(pass)
+	}
+	p := *pass
+	p.Report = func(diag analysis.Diagnostic) {
+		if !f.isKnown(pass.Fset, a, diag) {
+			pass.Report(diag)
+		}
+	}
+	return a.Run(&p)
+}
+
+// isKnown reports whether the diagnostic is covered by the baseline. A
+// baseline entry covers as many diagnostics as it has occurrences.
+func (f *baselineFilter) isKnown(fset *token.FileSet, a *analysis.Analyzer,
+	diag analysis.Diagnostic) bool {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := newDiagKey(fset, a, diag)
+	if known, ok := f.known[key]; ok {
+		return known
+	}
+	finding := f.src.finding(fset, a, diag)
+	known := f.counts[finding] > 0
+	if known {
+		f.counts[finding]--
+	}
+	f.known[key] = known
+	return known
+}
+
+// sources caches the content of source files.
+type sources map[string][]byte
+
+func newSources() sources {
+	return make(sources)
+}
+
+// finding returns the finding of the diagnostic. The file is relative to the
+// root of the module that contains it.
+func (s sources) finding(fset *token.FileSet, a *analysis.Analyzer,
+	diag analysis.Diagnostic) finding {
+
+	file := fset.Position(diag.Pos).Filename
+	if root := moduleRoot(filepath.Dir(file)); root != "" {
+		if rel, err := filepath.Rel(root, file); err == nil {
+			file = rel
+		}
+	}
+	return finding{
+		Analyzer: a.Name,
+		File:     filepath.ToSlash(file),
+		Expr:     s.text(fset, diag.Pos, diag.End),
+		Message:  diag.Message,
+	}
+}
+
+// moduleRoot returns the closest directory that contains a go.mod file,
+// starting at dir. The empty string is returned if there is none.
+func moduleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// text returns the source text between pos and end with normalized white
+// space. If the range is empty, the text of the line containing pos is
+// returned. The empty string is returned if the text cannot be determined.
+func (s sources) text(fset *token.FileSet, pos, end token.Pos) string {
+	tf := fset.File(pos)
+	if tf == nil {
+		return ""
+	}
+	content, ok := s[tf.Name()]
+	if !ok {
+		content, _ = os.ReadFile(tf.Name())
+		s[tf.Name()] = content
+	}
+	start, stop := tf.Offset(pos), tf.Offset(pos)
+	if end.IsValid() && end > pos && fset.File(end) == tf {
+		stop = tf.Offset(end)
+	} else {
+		start = bytes.LastIndexByte(content[:min(start, len(content))], '\n') + 1
+		if i := bytes.IndexByte(content[min(stop, len(content)):], '\n'); i >= 0 {
+			stop += i
+		} else {
+			stop = len(content)
+		}
+	}
+	if stop > len(content) {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(content[start:stop])), " ")
+}
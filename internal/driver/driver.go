@@ -0,0 +1,310 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package driver extends the singlechecker and multichecker drivers of
+// golang.org/x/tools for the command line tools.
+//
+// The diagnostics that are covered by a baseline of known findings are dropped
+// when they are reported. Writing or pruning the baseline, the structured
+// output formats and the key type report need the diagnostics of all packages,
+// which the x/tools drivers print before they exit. These reports are run by
+// this package instead.
+package driver
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Hooks that customize the reports. They are set before Prepare is called.
+var (
+	// Setup is called after the flags of a report are parsed and before the
+	// packages are loaded, if it is set. An error aborts the report.
+	Setup func() error
+	// RuleDoc returns the documentation of a rule, i.e., the category of a
+	// diagnostic, if it is set. It is used as help text by the structured
+	// output formats.
+	RuleDoc func(rule string) string
+	// Severity returns the severity of a rule for the given file, if it is
+	// set. For an empty file, it returns the severity of the root
+	// configuration. By default, all diagnostics are warnings.
+	Severity func(analyzer, rule, file string) string
+)
+
+var (
+	baselineFile  string
+	baselineFixed bool
+	outputFormat  string
+	jsonOutput    bool
+	keyTypes      bool
+	tests         bool
+)
+
+// Prepare registers the flags of the driver and returns the analyzers with the
+// baseline filter applied, to be run by singlechecker.Main or
+// multichecker.Main.
+//
+// If the command line requests a report, Prepare runs it and exits instead.
+// The exit code is 3 if diagnostics were printed, and 1 if loading or
+// analyzing failed. With -format=json, diagnostics do not affect the exit code.
+func Prepare(analyzers ...*analysis.Analyzer) []*analysis.Analyzer {
+	flag.StringVar(&baselineFile, "baseline", "", "file with known findings that are not "+
+		"reported, the file is created with the current findings if it does not exist")
+	flag.BoolVar(&baselineFixed, "baseline-fixed", false, "list the baseline entries "+
+		"that are no longer found instead of reporting new findings")
+	flag.StringVar(&outputFormat, "format", "text", "output format: text, json, sarif, "+
+		"checkstyle, junit or github")
+	flag.BoolVar(&keyTypes, "keytypes", false, "report the keys that are used with "+
+		"conflicting value types across all packages instead of the diagnostics")
+	if reportRequested(os.Args[1:]) {
+		report(analyzers)
+	}
+	return withBaseline(analyzers)
+}
+
+// reportRequested reports whether the arguments request a report. The
+// arguments are scanned before the flags are parsed, because the x/tools
+// drivers parse the flags themselves and do not return.
+func reportRequested(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		switch name {
+		case "format", "baseline":
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			if name == "format" && value != "text" {
+				return true
+			}
+			if name == "baseline" && value != "" {
+				if _, err := os.Stat(value); errors.Is(err, fs.ErrNotExist) {
+					return true
+				}
+			}
+		case "keytypes", "baseline-fixed":
+			if b, err := strconv.ParseBool(value); !hasValue || err == nil && b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// report parses the flags, runs the analyzers on the packages named on the
+// command line, prints the report and exits.
+func report(analyzers []*analysis.Analyzer) {
+	progname := filepath.Base(os.Args[0])
+	log.SetFlags(0)
+	log.SetPrefix(progname + ": ")
+
+	flag.BoolVar(&jsonOutput, "json", false, "emit JSON output, same as -format=json")
+	flag.BoolVar(&tests, "test", true, "indicates whether test files should be analyzed, too")
+	registerFlags(analyzers)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] [packages]\n\nFlags:\n", progname)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if jsonOutput {
+		outputFormat = "json"
+	}
+	if _, ok := formats[outputFormat]; !ok && outputFormat != "text" &&
+		outputFormat != "json" {
+
+		log.Fatalf("unknown output format: %q", outputFormat)
+	}
+	if baselineFixed && baselineFile == "" {
+		log.Fatal("-baseline-fixed requires -baseline")
+	}
+	if keyTypes {
+		if outputFormat != "text" && outputFormat != "json" {
+			log.Fatalf("-keytypes does not support output format: %q", outputFormat)
+		}
+		if err := enableKeyUses(analyzers); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if Setup != nil {
+		if err := Setup(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	os.Exit(run(progname, flag.Args(), analyzers))
+}
+
+// registerFlags registers the flags of the analyzers on the command line, the
+// same way as the x/tools drivers do. For a single analyzer, the flags are
+// registered with their name. Otherwise, they are prefixed with the analyzer
+// name, e.g., "logcheck.importpaths".
+func registerFlags(analyzers []*analysis.Analyzer) {
+	for _, a := range analyzers {
+		prefix := a.Name + "."
+		if len(analyzers) == 1 {
+			prefix = ""
+		}
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			if flag.Lookup(prefix+f.Name) != nil {
+				log.Printf("%s flag -%s would conflict with driver; skipping", a.Name, f.Name)
+				return
+			}
+			flag.Var(f.Value, prefix+f.Name, f.Usage)
+		})
+	}
+}
+
+func run(progname string, patterns []string, analyzers []*analysis.Analyzer) int {
+	exitcode := 0
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Tests: tests,
+	}, patterns...)
+	if err == nil && len(pkgs) == 0 {
+		err = fmt.Errorf("%v matched no packages", patterns)
+	}
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		exitcode = 1
+	}
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	if keyTypes {
+		graph.All()(func(act *checker.Action) bool {
+			if act.Err != nil {
+				log.Printf("%s: %v", act.Analyzer.Name, act.Err)
+				exitcode = 1
+			}
+			return true
+		})
+		conflicts := keyConflicts(collectKeyUses(graph))
+		if err := printKeyConflicts(os.Stdout, conflicts, outputFormat == "json"); err != nil {
+			log.Print(err)
+			return 1
+		}
+		if len(conflicts) > 0 && exitcode == 0 {
+			return 3
+		}
+		return exitcode
+	}
+
+	if baselineFile != "" {
+		b, err := readBaseline(baselineFile)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			findings, _ := collectFindings(graph)
+			if err := writeBaseline(baselineFile, newBaseline(findings)); err != nil {
+				log.Print(err)
+				return 1
+			}
+			log.Printf("wrote %d findings to %s", len(findings), baselineFile)
+			return exitcode
+		case err != nil:
+			log.Print(err)
+			return 1
+		case baselineFixed:
+			findings, _ := collectFindings(graph)
+			for _, e := range b.fixed(findings) {
+				fmt.Printf("%s: %s: %s (count=%d)\n", e.File, e.Analyzer, e.Message, e.Count)
+			}
+			return exitcode
+		}
+		filterKnown(graph, b)
+	}
+
+	if code := printDiagnostics(progname, graph); code != 0 {
+		return code
+	}
+	return exitcode
+}
+
+// printDiagnostics prints the diagnostics in the output format and returns
+// the exit code. With JSON output, the exit code is always zero.
+func printDiagnostics(progname string, graph *checker.Graph) int {
+	switch outputFormat {
+	case "json":
+		if err := graph.PrintJSON(os.Stdout); err != nil {
+			log.Print(err)
+			return 1
+		}
+		return 0
+	case "text":
+		if err := graph.PrintText(os.Stderr, -1); err != nil {
+			log.Print(err)
+			return 1
+		}
+	default:
+		if err := formats[outputFormat](os.Stdout, progname, collectResults(graph)); err != nil {
+			log.Print(err)
+			return 1
+		}
+		graph.All()(func(act *checker.Action) bool {
+			if act.Err != nil {
+				log.Printf("%s: %v", act.Analyzer.Name, act.Err)
+			}
+			return true
+		})
+	}
+	var errs, diags int
+	graph.All()(func(act *checker.Action) bool {
+		if act.Err != nil {
+			errs++
+		} else if act.IsRoot {
+			diags += len(act.Diagnostics)
+		}
+		return true
+	})
+	switch {
+	case errs > 0:
+		return 1
+	case diags > 0:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package driver
+
+import (
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	odd := finding{Analyzer: "logcheck", File: "a.go", Expr: `log.Info("msg", "key")`,
+		Message: "context should be even"}
+	key := finding{Analyzer: "logcheck", File: "a.go", Expr: `log.Info("msg", 1, 2)`,
+		Message: "key should be string"}
+	other := finding{Analyzer: "serrorscheck", File: "b.go", Expr: `serrors.New("msg", "key")`,
+		Message: "context should be even"}
+
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	if err := writeBaseline(file, newBaseline([]finding{odd, key, odd})); err != nil {
+		t.Fatal(err)
+	}
+	b, err := readBaseline(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []entry{{finding: odd, Count: 2}, {finding: key, Count: 1}}
+	if !reflect.DeepEqual(b.Entries, want) {
+		t.Errorf("entries: got %v, want %v", b.Entries, want)
+	}
+
+	known := b.known([]finding{odd, other, odd, odd})
+	if want := []bool{true, false, true, false}; !reflect.DeepEqual(known, want) {
+		t.Errorf("known: got %v, want %v", known, want)
+	}
+	fixed := b.fixed([]finding{odd, other})
+	want = []entry{{finding: odd, Count: 1}, {finding: key, Count: 1}}
+	if !reflect.DeepEqual(fixed, want) {
+		t.Errorf("fixed: got %v, want %v", fixed, want)
+	}
+}
+
+func TestSourcesText(t *testing.T) {
+	fset := token.NewFileSet()
+	content := []byte("package a\n\nfunc f() {\n\tlog.Info(\"msg\",\n\t\t\"key\")\n}\n")
+	tf := fset.AddFile("a.go", -1, len(content))
+	tf.SetLinesForContent(content)
+	src := sources{"a.go": content}
+
+	call := tf.Pos(len("package a\n\nfunc f() {\n\t"))
+	end := tf.Pos(len(content) - len("\n}\n"))
+	if got, want := src.text(fset, call, end), `log.Info("msg", "key")`; got != want {
+		t.Errorf("range: got %q, want %q", got, want)
+	}
+	if got, want := src.text(fset, call+4, token.NoPos), `log.Info("msg",`; got != want {
+		t.Errorf("line: got %q, want %q", got, want)
+	}
+}
+
+func TestReportRequested(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "baseline.json")
+	if err := writeBaseline(existing, newBaseline(nil)); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "baseline.json")
+	tests := map[string]struct {
+		args []string
+		want bool
+	}{
+		"packages":         {args: []string{"./..."}, want: false},
+		"x/tools flags":    {args: []string{"-fix", "-c", "2", "-json", "./..."}, want: false},
+		"text":             {args: []string{"-format=text", "./..."}, want: false},
+		"sarif":            {args: []string{"-format", "sarif", "./..."}, want: true},
+		"json":             {args: []string{"--format=json", "./..."}, want: true},
+		"keytypes":         {args: []string{"-keytypes", "./..."}, want: true},
+		"keytypes false":   {args: []string{"-keytypes=false", "./..."}, want: false},
+		"baseline fixed":   {args: []string{"-baseline-fixed", "./..."}, want: true},
+		"existing":         {args: []string{"-baseline=" + existing, "./..."}, want: false},
+		"missing":          {args: []string{"-baseline", missing, "./..."}, want: true},
+		"terminated":       {args: []string{"--", "-keytypes"}, want: false},
+		"value of x/tools": {args: []string{"-debug", "fpstv", "./..."}, want: false},
+	}
+	for name, test := range tests {
+		if got := reportRequested(test.args); got != test.want {
+			t.Errorf("%s: got %t, want %t", name, got, test.want)
+		}
+	}
+}
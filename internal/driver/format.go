@@ -0,0 +1,499 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package driver
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// formats maps the names of the machine-readable output formats to their
+// writers.
+var formats = map[string]func(w io.Writer, tool string, results []result) error{
+	"sarif":      writeSARIF,
+	"checkstyle": writeCheckstyle,
+	"junit":      writeJUnit,
+	"github":     writeGitHub,
+}
+
+// severityWarning is the default severity of the results.
+const severityWarning = "warning"
+
+// result is a diagnostic in a form that is independent of the file set.
+type result struct {
+	Analyzer string
+	// Rule is the diagnostic category, or the analyzer name if the
+	// diagnostic has no category.
+	Rule string
+	// Help describes the rule.
+	Help string
+	// HelpURI links to the documentation of the rule.
+	HelpURI  string
+	Severity string
+	// DefaultSeverity is the severity of the rule in the root
+	// configuration, which overrides for subdirectories do not change.
+	DefaultSeverity string
+	Pos, End        token.Position
+	Message         string
+	Fixes           []resultFix
+}
+
+// resultFix is a suggested fix of a result.
+type resultFix struct {
+	Message string
+	Edits   []resultEdit
+}
+
+// resultEdit is a text edit of a suggested fix.
+type resultEdit struct {
+	Pos, End token.Position
+	NewText  string
+}
+
+// relativePath returns the slash-separated path of the file relative to wd, if
+// the file is inside of wd. Otherwise, the file is returned unchanged.
+func relativePath(wd, file string) string {
+	if rel, err := filepath.Rel(wd, file); err == nil && wd != "" &&
+		!strings.HasPrefix(rel, "..") {
+
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+// collectResults returns the diagnostics of the root actions sorted by
+// position. Duplicate diagnostics are only returned once. File names are
+// relative to the working directory, if possible.
+func collectResults(graph *checker.Graph) []result {
+	wd, _ := os.Getwd()
+	relative := func(posn token.Position) token.Position {
+		posn.Filename = relativePath(wd, posn.Filename)
+		return posn
+	}
+	var results []result
+	seen := make(map[diagKey]bool)
+	for _, act := range graph.Roots {
+		fset := act.Package.Fset
+		for _, diag := range act.Diagnostics {
+			key := newDiagKey(fset, act.Analyzer, diag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			r := newResult(act.Analyzer, diag)
+			if Severity != nil {
+				r.Severity = Severity(r.Analyzer, r.Rule, key.pos.Filename)
+				r.DefaultSeverity = Severity(r.Analyzer, r.Rule, "")
+			}
+			r.Pos, r.End = relative(key.pos), relative(key.end)
+			if !diag.End.IsValid() {
+				r.End = r.Pos
+			}
+			for _, sf := range diag.SuggestedFixes {
+				f := resultFix{Message: sf.Message}
+				for _, te := range sf.TextEdits {
+					end := te.End
+					if !end.IsValid() {
+						end = te.Pos
+					}
+					f.Edits = append(f.Edits, resultEdit{
+						Pos:     relative(fset.Position(te.Pos)),
+						End:     relative(fset.Position(end)),
+						NewText: string(te.NewText),
+					})
+				}
+				r.Fixes = append(r.Fixes, f)
+			}
+			results = append(results, r)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Pos, results[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return results
+}
+
+// newResult creates a result for the diagnostic without positions.
+func newResult(a *analysis.Analyzer, diag analysis.Diagnostic) result {
+	rule := diag.Category
+	if rule == "" {
+		rule = a.Name
+	}
+	uri := diag.URL
+	if uri == "" {
+		uri = a.URL
+	}
+	help := firstLine(a.Doc)
+	if RuleDoc != nil {
+		if doc := RuleDoc(rule); doc != "" {
+			help = doc
+		}
+	}
+	return result{
+		Analyzer:        a.Name,
+		Rule:            rule,
+		Help:            help,
+		HelpURI:         uri,
+		Severity:        severityWarning,
+		DefaultSeverity: severityWarning,
+		Message:         diag.Message,
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// sarifLevels maps the severities to SARIF levels.
+var sarifLevels = map[string]string{
+	"error":   "error",
+	"warning": "warning",
+	"info":    "note",
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifText          `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	Help                 sarifText          `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]string  `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifText             `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion `json:"deletedRegion"`
+	InsertedContent sarifText   `json:"insertedContent"`
+}
+
+func newSARIFRegion(pos, end token.Position) sarifRegion {
+	return sarifRegion{
+		StartLine:   pos.Line,
+		StartColumn: pos.Column,
+		EndLine:     end.Line,
+		EndColumn:   end.Column,
+	}
+}
+
+// writeSARIF writes the results as a SARIF 2.1.0 log.
+func writeSARIF(w io.Writer, tool string, results []result) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: tool, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	rules := make(map[string]int)
+	for _, r := range results {
+		if _, ok := rules[r.Rule]; !ok {
+			rules[r.Rule] = len(run.Tool.Driver.Rules)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:                   r.Rule,
+				ShortDescription:     sarifText{Text: r.Help},
+				HelpURI:              r.HelpURI,
+				Help:                 sarifText{Text: r.Help},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevels[r.DefaultSeverity]},
+				Properties:           map[string]string{"analyzer": r.Analyzer},
+			})
+		}
+		res := sarifResult{
+			RuleID:    r.Rule,
+			RuleIndex: rules[r.Rule],
+			Level:     sarifLevels[r.Severity],
+			Message:   sarifText{Text: r.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: r.Pos.Filename},
+				Region:           newSARIFRegion(r.Pos, r.End),
+			}}},
+		}
+		for _, f := range r.Fixes {
+			fix := sarifFix{Description: sarifText{Text: f.Message}}
+			changes := make(map[string]int)
+			for _, e := range f.Edits {
+				i, ok := changes[e.Pos.Filename]
+				if !ok {
+					i = len(fix.ArtifactChanges)
+					changes[e.Pos.Filename] = i
+					fix.ArtifactChanges = append(fix.ArtifactChanges, sarifArtifactChange{
+						ArtifactLocation: sarifArtifactLocation{URI: e.Pos.Filename},
+					})
+				}
+				fix.ArtifactChanges[i].Replacements = append(
+					fix.ArtifactChanges[i].Replacements, sarifReplacement{
+						DeletedRegion:   newSARIFRegion(e.Pos, e.End),
+						InsertedContent: sarifText{Text: e.NewText},
+					})
+			}
+			res.Fixes = append(res.Fixes, fix)
+		}
+		run.Results = append(run.Results, res)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes the results as a Checkstyle XML report. The source
+// of an error is the tool name followed by the analyzer and the rule.
+func writeCheckstyle(w io.Writer, tool string, results []result) error {
+	report := checkstyleReport{Version: "4.3"}
+	files := make(map[string]int)
+	for _, r := range results {
+		i, ok := files[r.Pos.Filename]
+		if !ok {
+			i = len(report.Files)
+			files[r.Pos.Filename] = i
+			report.Files = append(report.Files, checkstyleFile{Name: r.Pos.Filename})
+		}
+		report.Files[i].Errors = append(report.Files[i].Errors, checkstyleError{
+			Line:     r.Pos.Line,
+			Column:   r.Pos.Column,
+			Severity: r.Severity,
+			Message:  r.Message,
+			Source:   strings.Join(uniqueParts(tool, r.Analyzer, r.Rule), "."),
+		})
+	}
+	return writeXML(w, report)
+}
+
+// uniqueParts returns the parts without consecutive duplicates.
+func uniqueParts(parts ...string) []string {
+	var unique []string
+	for _, part := range parts {
+		if len(unique) == 0 || unique[len(unique)-1] != part {
+			unique = append(unique, part)
+		}
+	}
+	return unique
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the results as a JUnit XML report with a test suite per
+// analyzer and a failed test case per result. Analyzers without results are
+// reported with a single passed test case.
+func writeJUnit(w io.Writer, tool string, results []result) error {
+	report := junitSuites{}
+	suites := make(map[string]int)
+	for _, r := range results {
+		i, ok := suites[r.Analyzer]
+		if !ok {
+			i = len(report.Suites)
+			suites[r.Analyzer] = i
+			report.Suites = append(report.Suites, junitSuite{Name: r.Analyzer})
+		}
+		suite := &report.Suites[i]
+		suite.Tests++
+		suite.Failures++
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:      fmt.Sprintf("%s:%d:%d", r.Pos.Filename, r.Pos.Line, r.Pos.Column),
+			ClassName: r.Pos.Filename,
+			Failure: &junitFailure{
+				Message: r.Message,
+				Type:    r.Rule,
+				Text: fmt.Sprintf("%s:%d:%d: %s", r.Pos.Filename, r.Pos.Line, r.Pos.Column,
+					r.Message),
+			},
+		})
+	}
+	if len(report.Suites) == 0 {
+		report.Suites = append(report.Suites, junitSuite{
+			Name:  tool,
+			Tests: 1,
+			Cases: []junitCase{{Name: tool, ClassName: tool}},
+		})
+	}
+	sort.SliceStable(report.Suites, func(i, j int) bool {
+		return report.Suites[i].Name < report.Suites[j].Name
+	})
+	return writeXML(w, report)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// githubCommands maps the severities to GitHub Actions workflow commands.
+var githubCommands = map[string]string{
+	"error":   "error",
+	"warning": "warning",
+	"info":    "notice",
+}
+
+// writeGitHub writes the results as GitHub Actions workflow commands, such
+// that they are shown as annotations.
+func writeGitHub(w io.Writer, tool string, results []result) error {
+	for _, r := range results {
+		_, err := fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d,endLine=%d,endColumn=%d,"+
+			"title=%s::%s\n", githubCommands[r.Severity], githubProperty(r.Pos.Filename),
+			r.Pos.Line, r.Pos.Column, r.End.Line, r.End.Column,
+			githubProperty(strings.Join(uniqueParts(r.Analyzer, r.Rule), " ")), githubData(r.Message))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubData escapes the message of a workflow command.
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubProperty escapes a property value of a workflow command.
+func githubProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(githubData(s))
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package driver
+
+import (
+	"bytes"
+	"flag"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// testResults covers results with and without fixes, multiple rules and
+// analyzers, and messages that need escaping.
+var testResults = []result{
+	{
+		Analyzer:        "logcheck",
+		Rule:            "LOG001",
+		Help:            "reports invalid log calls",
+		HelpURI:         "https://example.com/rules#LOG001",
+		Severity:        severityWarning,
+		DefaultSeverity: severityWarning,
+		Pos:             token.Position{Filename: "pkg/a.go", Line: 10, Column: 19},
+		End:             token.Position{Filename: "pkg/a.go", Line: 10, Column: 24},
+		Message:         `context should be even: len=1 ctx=["key"] expr="log.Info(\"msg\", \"key\")"`,
+		Fixes: []resultFix{{
+			Message: "Add placeholder value",
+			Edits: []resultEdit{{
+				Pos:     token.Position{Filename: "pkg/a.go", Line: 10, Column: 24},
+				End:     token.Position{Filename: "pkg/a.go", Line: 10, Column: 24},
+				NewText: ", nil",
+			}},
+		}},
+	},
+	{
+		Analyzer: "logcheck",
+		Rule:     "LOG002",
+		Help:     "reports invalid log calls",
+		// The severity is overridden for the directory of the file.
+		Severity:        "error",
+		DefaultSeverity: severityWarning,
+		Pos:             token.Position{Filename: "pkg/a.go", Line: 12, Column: 19},
+		End:             token.Position{Filename: "pkg/a.go", Line: 12, Column: 20},
+		Message:         "key should be string: type=\"int\" name=\"1\"\nsecond line with 100%",
+	},
+	{
+		Analyzer:        "serrorscheck",
+		Rule:            "serrorscheck",
+		Help:            "reports invalid serrors calls",
+		Severity:        "info",
+		DefaultSeverity: "info",
+		Pos:             token.Position{Filename: "pkg/b.go", Line: 3, Column: 9},
+		End:             token.Position{Filename: "pkg/b.go", Line: 3, Column: 9},
+		Message:         `should have context: expr="serrors.WithCtx(err)"`,
+	},
+}
+
+func TestFormats(t *testing.T) {
+	for name, write := range formats {
+		t.Run(name, func(t *testing.T) {
+			for suffix, results := range map[string][]result{"": testResults, ".empty": nil} {
+				var buf bytes.Buffer
+				if err := write(&buf, "gochecks", results); err != nil {
+					t.Fatal(err)
+				}
+				golden := filepath.Join("testdata", name+suffix+".golden")
+				if *update {
+					if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(buf.Bytes(), want) {
+					t.Errorf("%s: got\n%s\nwant\n%s", golden, buf.Bytes(), want)
+				}
+			}
+		})
+	}
+}
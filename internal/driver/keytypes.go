@@ -0,0 +1,168 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/oncilla/gochecks/kvcheck"
+)
+
+// maxExamples is the number of example locations listed per value type.
+const maxExamples = 3
+
+// keyConflict is a key that is used with more than one value type.
+type keyConflict struct {
+	Key      string       `json:"key"`
+	Variants []keyVariant `json:"variants"`
+}
+
+// keyVariant is a value type that a key is used with.
+type keyVariant struct {
+	Type     string   `json:"type"`
+	Count    int      `json:"count"`
+	Examples []string `json:"examples"`
+}
+
+// enableKeyUses sets the keyuses flag of all analyzers that have it. It fails
+// if none of the analyzers records key uses.
+func enableKeyUses(analyzers []*analysis.Analyzer) error {
+	var found bool
+	for _, a := range analyzers {
+		if f := a.Flags.Lookup("keyuses"); f != nil {
+			if err := f.Value.Set("true"); err != nil {
+				return err
+			}
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("-keytypes is not supported by the analyzers")
+	}
+	return nil
+}
+
+// collectKeyUses returns the key uses recorded in the package facts of the
+// root actions. The positions are relative to the working directory, if
+// possible. Uses that are recorded for a package and its test variant are
+// only returned once.
+func collectKeyUses(graph *checker.Graph) []kvcheck.KeyUse {
+	wd, _ := os.Getwd()
+	var uses []kvcheck.KeyUse
+	seen := make(map[kvcheck.KeyUse]bool)
+	for _, act := range graph.Roots {
+		for _, pf := range act.AllPackageFacts() {
+			if pf.Package != act.Package.Types {
+				continue
+			}
+			recorded, ok := kvcheck.KeyUses(pf.Fact)
+			if !ok {
+				continue
+			}
+			for _, use := range recorded {
+				use.Pos = relativePath(wd, use.Pos)
+				if !seen[use] {
+					seen[use] = true
+					uses = append(uses, use)
+				}
+			}
+		}
+	}
+	return uses
+}
+
+// keyConflicts returns the keys that are used with more than one value type,
+// sorted by key. The variants are sorted by decreasing count, their examples
+// are the first uses in the order they were recorded.
+func keyConflicts(uses []kvcheck.KeyUse) []keyConflict {
+	byKey := make(map[string]map[string][]string)
+	for _, use := range uses {
+		if byKey[use.Key] == nil {
+			byKey[use.Key] = make(map[string][]string)
+		}
+		byKey[use.Key][use.Type] = append(byKey[use.Key][use.Type], use.Pos)
+	}
+	var conflicts []keyConflict
+	for key, types := range byKey {
+		if len(types) < 2 {
+			continue
+		}
+		c := keyConflict{Key: key}
+		for t, positions := range types {
+			examples := positions
+			if len(examples) > maxExamples {
+				examples = examples[:maxExamples]
+			}
+			c.Variants = append(c.Variants, keyVariant{
+				Type:     t,
+				Count:    len(positions),
+				Examples: examples,
+			})
+		}
+		sort.Slice(c.Variants, func(i, j int) bool {
+			vi, vj := c.Variants[i], c.Variants[j]
+			if vi.Count != vj.Count {
+				return vi.Count > vj.Count
+			}
+			return vi.Type < vj.Type
+		})
+		conflicts = append(conflicts, c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
+
+// printKeyConflicts prints the conflicts as text or as JSON.
+func printKeyConflicts(w io.Writer, conflicts []keyConflict, asJSON bool) error {
+	if asJSON {
+		if conflicts == nil {
+			conflicts = []keyConflict{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(conflicts)
+	}
+	for _, c := range conflicts {
+		if _, err := fmt.Fprintf(w, "key %q is used with %d value types:\n", c.Key,
+			len(c.Variants)); err != nil {
+			return err
+		}
+		for _, v := range c.Variants {
+			if _, err := fmt.Fprintf(w, "\t%s (count=%d): %s\n", v.Type, v.Count,
+				strings.Join(v.Examples, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
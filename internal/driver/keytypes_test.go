@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package driver
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/oncilla/gochecks/kvcheck"
+)
+
+func TestKeyConflicts(t *testing.T) {
+	uses := []kvcheck.KeyUse{
+		{Key: "ia", Type: "example.com/addr.IA", Pos: "a.go:1:1"},
+		{Key: "ia", Type: "string", Pos: "a.go:2:1"},
+		{Key: "ia", Type: "example.com/addr.IA", Pos: "b.go:1:1"},
+		{Key: "ia", Type: "example.com/addr.IA", Pos: "b.go:2:1"},
+		{Key: "ia", Type: "example.com/addr.IA", Pos: "b.go:3:1"},
+		{Key: "count", Type: "int", Pos: "a.go:3:1"},
+		{Key: "count", Type: "int", Pos: "a.go:4:1"},
+		{Key: "duration", Type: "time.Duration", Pos: "a.go:5:1"},
+		{Key: "duration", Type: "int64", Pos: "c.go:1:1"},
+	}
+	conflicts := keyConflicts(uses)
+	want := []keyConflict{
+		{Key: "duration", Variants: []keyVariant{
+			{Type: "int64", Count: 1, Examples: []string{"c.go:1:1"}},
+			{Type: "time.Duration", Count: 1, Examples: []string{"a.go:5:1"}},
+		}},
+		{Key: "ia", Variants: []keyVariant{
+			{Type: "example.com/addr.IA", Count: 4, Examples: []string{"a.go:1:1", "b.go:1:1",
+				"b.go:2:1"}},
+			{Type: "string", Count: 1, Examples: []string{"a.go:2:1"}},
+		}},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("got %+v, want %+v", conflicts, want)
+	}
+
+	var buf bytes.Buffer
+	if err := printKeyConflicts(&buf, conflicts, false); err != nil {
+		t.Fatal(err)
+	}
+	wantText := `key "duration" is used with 2 value types:
+	int64 (count=1): c.go:1:1
+	time.Duration (count=1): a.go:5:1
+key "ia" is used with 2 value types:
+	example.com/addr.IA (count=4): a.go:1:1, b.go:1:1, b.go:2:1
+	string (count=1): a.go:2:1
+`
+	if buf.String() != wantText {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), wantText)
+	}
+
+	buf.Reset()
+	if err := printKeyConflicts(&buf, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("empty JSON: got %q", buf.String())
+	}
+}
@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Call is a checked call, as recorded for a catalog of the messages and the
+// context that a program emits.
+type Call struct {
+	// ImportPath, Recv and Name identify the called function, see CallSpec.
+	// For calls of wrappers, Name is the name of the wrapper and Recv is
+	// empty.
+	ImportPath string
+	Recv       string
+	Name       string
+	// Function is the function that contains the call, e.g., "run" or
+	// "(*Server).Run". It is empty for calls outside of functions.
+	Function string
+	// Message is the message, if it is constant.
+	Message string
+	// Keys are the constant keys of the call, and the types of their values.
+	// The type is empty if it is unknown.
+	Keys []KeyUse
+	// Pos is the position of the call in the form "file:line:column".
+	Pos string
+}
+
+// Calls returns the calls recorded in the package fact, if it is a call fact
+// of an analyzer created by this package. The facts are exported if the
+// catalog flag of the analyzer is set.
+func Calls(fact analysis.Fact) ([]Call, bool) {
+	f, ok := fact.(interface{ calls() []Call })
+	if !ok {
+		return nil, false
+	}
+	return f.calls(), true
+}
+
+// keyedCallsFact is the call fact of the analyzers created with key K.
+type keyedCallsFact[K any] struct {
+	Calls []Call
+}
+
+func (*keyedCallsFact[K]) AFact() {}
+
+func (f *keyedCallsFact[K]) calls() []Call {
+	return f.Calls
+}
+
+func (f *keyedCallsFact[K]) String() string {
+	return fmt.Sprintf("calls(n=%d)", len(f.Calls))
+}
+
+// newCall records the call that matches the spec.
+func newCall(pass *analysis.Pass, file *ast.File, ce *ast.CallExpr, spec CallSpec) Call {
+	call := Call{
+		ImportPath: spec.ImportPath,
+		Recv:       spec.Recv,
+		Name:       spec.Name,
+		Function:   enclosingFunc(file, ce.Pos()),
+		Pos:        pass.Fset.Position(ce.Pos()).String(),
+	}
+	if spec.Msg >= 0 && spec.Msg < len(ce.Args) {
+		call.Message, _ = constString(pass, ce.Args[spec.Msg])
+	}
+	varargs, ok := contextArgs(pass, ce, spec)
+	if !ok || hasBadExpr(ce) {
+		return call
+	}
+	kvs := keyValues(pass, varargs, spec.AttrTypes)
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok {
+			continue
+		}
+		use := KeyUse{Key: key, Pos: pass.Fset.Position(kvs[i].Pos()).String()}
+		if i+1 < len(kvs) {
+			if t := typeOf(pass, kvs[i+1]); t != nil {
+				use.Type = qualifiedType(t)
+			}
+		}
+		call.Keys = append(call.Keys, use)
+	}
+	return call
+}
+
+// contextArgs returns the key/value arguments of the call. Slices that are
+// passed with an ellipsis are reconstructed, if possible.
+func contextArgs(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec) ([]ast.Expr, bool) {
+	if len(ce.Args) <= spec.Start {
+		return nil, true
+	}
+	if ce.Ellipsis == token.NoPos {
+		return ce.Args[spec.Start:], true
+	}
+	return resolveEllipsis(pass, ce)
+}
+
+// enclosingFunc returns the name of the function declaration that contains
+// the position. Methods are qualified with their receiver type, e.g.,
+// "(*Server).Run" or "Server.Run".
+func enclosingFunc(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < fd.Pos() || pos >= fd.End() {
+			continue
+		}
+		if fd.Recv == nil || len(fd.Recv.List) == 0 {
+			return fd.Name.Name
+		}
+		recv := fd.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			return fmt.Sprintf("(*%s).%s", recvName(star.X), fd.Name.Name)
+		}
+		return fmt.Sprintf("%s.%s", recvName(recv), fd.Name.Name)
+	}
+	return ""
+}
+
+// recvName returns the name of the receiver type without type parameters.
+func recvName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.IndexExpr:
+		return recvName(e.X)
+	case *ast.IndexListExpr:
+		return recvName(e.X)
+	case *ast.ParenExpr:
+		return recvName(e.X)
+	}
+	return "?"
+}
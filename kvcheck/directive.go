@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// kvargsDirective marks functions and interface methods that take key/value
+// context which cannot be inferred, e.g.:
+//
+//	//gochecks:kvargs start=1 msg=0
+//	Report(msg string, ctx ...interface{})
+//
+// The start option is the index of the first key/value argument and must be
+// set. The msg option is the index of the message argument. The directive
+// applies to all analyzers, unless the analyzers option restricts it to a
+// comma-separated list of analyzers.
+const kvargsDirective = "//gochecks:kvargs"
+
+// exportDirectives exports a wrapper fact for all functions and interface
+// methods in the package that are annotated with the kvargs directive.
+// Invalid directives are reported.
+func (c *checker) exportDirectives(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				c.exportDirective(pass, decl.Doc, decl.Name)
+			case *ast.GenDecl:
+				for _, s := range decl.Specs {
+					ts, ok := s.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					iface, ok := ts.Type.(*ast.InterfaceType)
+					if !ok {
+						continue
+					}
+					for _, field := range iface.Methods.List {
+						for _, name := range field.Names {
+							c.exportDirective(pass, field.Doc, name)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// exportDirective exports a wrapper fact for the named function if the doc
+// comment contains a kvargs directive that applies to the analyzer.
+func (c *checker) exportDirective(pass *analysis.Pass, doc *ast.CommentGroup, name *ast.Ident) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		if comment.Text != kvargsDirective &&
+			!strings.HasPrefix(comment.Text, kvargsDirective+" ") {
+			continue
+		}
+		fn, ok := pass.TypesInfo.Defs[name].(*types.Func)
+		if !ok {
+			return
+		}
+		options := comment.Text[len(kvargsDirective):]
+		if analyzers, ok := directiveAnalyzers(options); ok && len(analyzers) > 0 &&
+			!analyzers[c.name] {
+			return
+		}
+		spec, err := parseDirective(options, fn)
+		if err != nil {
+			reportf(pass, ruleInvalidDirective, name.Pos(),
+				"invalid kvargs directive: err=%q func=%q", err, fn.Name())
+			return
+		}
+		spec.ImportPath = pass.Pkg.Path()
+		pass.ExportObjectFact(fn, c.newFact(spec))
+		return
+	}
+}
+
+// directiveAnalyzers returns the set of analyzers of the analyzers option, and
+// whether the option is set. Empty names are ignored, such that a directive
+// with an empty set is bound to no analyzer and is reported by all of them.
+func directiveAnalyzers(options string) (map[string]bool, bool) {
+	analyzers := make(map[string]bool)
+	set := false
+	for _, option := range strings.Fields(options) {
+		if value, ok := strings.CutPrefix(option, "analyzers="); ok {
+			set = true
+			for _, name := range strings.Split(value, ",") {
+				if name != "" {
+					analyzers[name] = true
+				}
+			}
+		}
+	}
+	return analyzers, set
+}
+
+// parseDirective parses the options of a kvargs directive and validates them
+// against the signature of the function.
+func parseDirective(options string, fn *types.Func) (CallSpec, error) {
+	spec := CallSpec{Name: fn.Name(), Msg: NoMsg, Start: -1}
+	for _, option := range strings.Fields(options) {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return CallSpec{}, fmt.Errorf("option %q is not of the form key=value", option)
+		}
+		switch key {
+		case "start", "msg":
+			i, err := strconv.Atoi(value)
+			if err != nil || i < 0 {
+				return CallSpec{}, fmt.Errorf("%s must be a non-negative index", key)
+			}
+			if key == "start" {
+				spec.Start = i
+			} else {
+				spec.Msg = i
+			}
+		case "analyzers":
+			// The analyzers are handled by directiveAnalyzers.
+			if analyzers, _ := directiveAnalyzers(option); len(analyzers) == 0 {
+				return CallSpec{}, fmt.Errorf("analyzers must not be empty")
+			}
+		default:
+			return CallSpec{}, fmt.Errorf("unknown option %q", key)
+		}
+	}
+	if spec.Start < 0 {
+		return CallSpec{}, fmt.Errorf("start must be set")
+	}
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() || !isEmptyInterfaceSlice(params.At(params.Len()-1).Type()) {
+		return CallSpec{}, fmt.Errorf("function must have a variadic empty interface " +
+			"parameter")
+	}
+	if spec.Start > params.Len()-1 {
+		return CallSpec{}, fmt.Errorf("start must not be after the variadic parameter")
+	}
+	if spec.Msg >= spec.Start {
+		return CallSpec{}, fmt.Errorf("msg must be before start")
+	}
+	return spec, nil
+}
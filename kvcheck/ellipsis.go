@@ -0,0 +1,279 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// resolveEllipsis reconstructs the elements of the slice that is passed with
+// ellipsis as the variadic argument of the call. It reports false if the
+// elements cannot be determined statically.
+//
+// Slice literals, calls to append and local slice variables are supported. A
+// local variable is resolved if it is declared and only appended to in the
+// statements that directly precede the call in the same block, and it is not
+// used anywhere else. In particular, a slice that is shared by multiple calls
+// is not resolved.
+func resolveEllipsis(pass *analysis.Pass, ce *ast.CallExpr) ([]ast.Expr, bool) {
+	file := enclosingFile(pass, ce.Pos())
+	if file == nil {
+		return nil, false
+	}
+	path, _ := astutil.PathEnclosingInterval(file, ce.Pos(), ce.End())
+	r := &resolver{pass: pass, path: path}
+	r.block, r.end = r.enclosingStmt()
+	arg := ce.Args[len(ce.Args)-1]
+	elems, ok := r.resolve(arg)
+	if !ok {
+		return nil, false
+	}
+	r.reads = append(r.reads, arg)
+	for _, v := range r.vars {
+		if !r.unmodified(v) {
+			return nil, false
+		}
+	}
+	return elems, true
+}
+
+// resolver resolves slice expressions that are used in the call at the end of
+// path.
+type resolver struct {
+	pass *analysis.Pass
+	path []ast.Node
+	// block is the innermost block that contains the call, and end is the
+	// index of the statement that is currently being resolved. Variables are
+	// only resolved from the statements in the block before end.
+	block *ast.BlockStmt
+	end   int
+	// vars are the resolved variables.
+	vars []*types.Var
+	// reads are the nodes that read the resolved variables to reconstruct
+	// the slice.
+	reads []ast.Node
+}
+
+func (r *resolver) resolve(expr ast.Expr) ([]ast.Expr, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CompositeLit:
+		t := typeOf(r.pass, e)
+		if t == nil {
+			return nil, false
+		}
+		if _, ok := t.Underlying().(*types.Slice); !ok {
+			return nil, false
+		}
+		for _, elt := range e.Elts {
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				return nil, false
+			}
+		}
+		return e.Elts, true
+	case *ast.CallExpr:
+		if !isBuiltin(r.pass, e, "append") || len(e.Args) == 0 {
+			return nil, false
+		}
+		elems, ok := r.resolve(e.Args[0])
+		if !ok {
+			return nil, false
+		}
+		if e.Ellipsis == token.NoPos {
+			return append(elems[:len(elems):len(elems)], e.Args[1:]...), true
+		}
+		rest, ok := r.resolve(e.Args[1])
+		if !ok {
+			return nil, false
+		}
+		return append(elems[:len(elems):len(elems)], rest...), true
+	case *ast.Ident:
+		v, ok := r.pass.TypesInfo.Uses[e].(*types.Var)
+		if !ok {
+			return nil, false
+		}
+		return r.resolveVar(v)
+	}
+	return nil, false
+}
+
+// resolveVar resolves a local slice variable.
+func (r *resolver) resolveVar(v *types.Var) ([]ast.Expr, bool) {
+	if r.block == nil {
+		return nil, false
+	}
+	// A variable that is read in the declaration of another variable must
+	// only be resolved up to that declaration, and not up to the call.
+	end := r.end
+	defer func() { r.end = end }()
+	var elems []ast.Expr
+	declared := false
+	for i, stmt := range r.block.List[:end] {
+		r.end = i
+		if !declared {
+			if values, ok := r.declaration(stmt, v); ok {
+				elems, declared = values, true
+				r.reads = append(r.reads, stmt)
+			}
+			continue
+		}
+		if values, ok := r.appendStmt(stmt, v); ok {
+			elems = append(elems[:len(elems):len(elems)], values...)
+			r.reads = append(r.reads, stmt)
+		}
+	}
+	if !declared {
+		return nil, false
+	}
+	r.vars = append(r.vars, v)
+	return elems, true
+}
+
+// declaration returns the initial elements if the statement declares the
+// variable.
+func (r *resolver) declaration(stmt ast.Stmt, v *types.Var) ([]ast.Expr, bool) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.DEFINE || len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+			return nil, false
+		}
+		if id, ok := s.Lhs[0].(*ast.Ident); !ok || r.pass.TypesInfo.Defs[id] != v {
+			return nil, false
+		}
+		return r.resolve(s.Rhs[0])
+	case *ast.DeclStmt:
+		gd, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR || len(gd.Specs) != 1 {
+			return nil, false
+		}
+		vs := gd.Specs[0].(*ast.ValueSpec)
+		if len(vs.Names) != 1 || r.pass.TypesInfo.Defs[vs.Names[0]] != v {
+			return nil, false
+		}
+		if len(vs.Values) == 0 {
+			_, ok := v.Type().Underlying().(*types.Slice)
+			return nil, ok
+		}
+		return r.resolve(vs.Values[0])
+	}
+	return nil, false
+}
+
+// appendStmt returns the appended elements if the statement is of the form
+// v = append(v, ...).
+func (r *resolver) appendStmt(stmt ast.Stmt, v *types.Var) ([]ast.Expr, bool) {
+	s, ok := stmt.(*ast.AssignStmt)
+	if !ok || s.Tok != token.ASSIGN || len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+		return nil, false
+	}
+	if id, ok := s.Lhs[0].(*ast.Ident); !ok || r.pass.TypesInfo.Uses[id] != v {
+		return nil, false
+	}
+	call, ok := s.Rhs[0].(*ast.CallExpr)
+	if !ok || !isBuiltin(r.pass, call, "append") || len(call.Args) < 1 {
+		return nil, false
+	}
+	if id, ok := call.Args[0].(*ast.Ident); !ok || r.pass.TypesInfo.Uses[id] != v {
+		return nil, false
+	}
+	if call.Ellipsis == token.NoPos {
+		return call.Args[1:], true
+	}
+	return r.resolve(call.Args[1])
+}
+
+// unmodified reports whether the variable is only used to reconstruct the
+// slice. Any other use might modify the elements, e.g., passing the slice to a
+// function or to copy, assigning an element, or aliasing the slice.
+func (r *resolver) unmodified(v *types.Var) bool {
+	body := r.enclosingFunc()
+	if body == nil {
+		return false
+	}
+	ok := true
+	ast.Inspect(body, func(n ast.Node) bool {
+		if id, isID := n.(*ast.Ident); isID && r.pass.TypesInfo.ObjectOf(id) == v {
+			ok = r.isRead(id)
+		}
+		return ok
+	})
+	return ok
+}
+
+// isRead reports whether the identifier is part of a node that reads the
+// resolved variables.
+func (r *resolver) isRead(id *ast.Ident) bool {
+	for _, n := range r.reads {
+		if n.Pos() <= id.Pos() && id.End() <= n.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// enclosingStmt returns the innermost block that contains the call, and the
+// index of the statement in the block that contains the call.
+func (r *resolver) enclosingStmt() (*ast.BlockStmt, int) {
+	for i := 1; i < len(r.path); i++ {
+		switch n := r.path[i].(type) {
+		case *ast.BlockStmt:
+			for idx, stmt := range n.List {
+				if stmt == r.path[i-1] {
+					return n, idx
+				}
+			}
+			return nil, 0
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil, 0
+		}
+	}
+	return nil, 0
+}
+
+// enclosingFunc returns the body of the innermost function that contains the
+// call.
+func (r *resolver) enclosingFunc() *ast.BlockStmt {
+	for _, n := range r.path {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n.Body
+		case *ast.FuncDecl:
+			return n.Body
+		}
+	}
+	return nil
+}
+
+// enclosingFile returns the file that contains the position.
+func enclosingFile(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, file := range pass.Files {
+		if file.Pos() <= pos && pos < file.End() {
+			return file
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// wrapperFact is exported for functions that forward their trailing variadic
+// parameter to a checked call. Calls of these functions are checked according
+// to their spec.
+//
+// Drivers require every fact type to belong to a single analyzer. Thus, every
+// analyzer uses its own instantiation of keyedWrapperFact.
+type wrapperFact interface {
+	analysis.Fact
+	spec() CallSpec
+}
+
+// keyedWrapperFact is the wrapper fact of the analyzers created with key K.
+type keyedWrapperFact[K any] struct {
+	Spec CallSpec
+}
+
+func (*keyedWrapperFact[K]) AFact() {}
+
+func (f *keyedWrapperFact[K]) spec() CallSpec {
+	return f.Spec
+}
+
+func (f *keyedWrapperFact[K]) String() string {
+	return fmt.Sprintf("kvwrapper(msg=%d start=%d)", f.Spec.Msg, f.Spec.Start)
+}
+
+// wrapperSpec returns the spec of the called function, if it is a wrapper.
+func (c *checker) wrapperSpec(pass *analysis.Pass, ce *ast.CallExpr) (CallSpec, bool) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, ce).(*types.Func)
+	if !ok {
+		return CallSpec{}, false
+	}
+	fact := c.newFact(CallSpec{})
+	if !pass.ImportObjectFact(fn.Origin(), fact) {
+		return CallSpec{}, false
+	}
+	return fact.spec(), true
+}
+
+// exportWrappers exports a wrapper fact for all functions in the package that
+// forward their trailing variadic parameter to a checked call. Wrappers of
+// wrappers are detected by iterating until no more facts are exported.
+func (c *checker) exportWrappers(pass *analysis.Pass, recvs map[recvKey]*types.TypeName) {
+	var decls []*ast.FuncDecl
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+				decls = append(decls, fd)
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range decls {
+			fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok || pass.ImportObjectFact(fn, c.newFact(CallSpec{})) {
+				continue
+			}
+			if spec, ok := c.forwardedSpec(pass, decl, fn, recvs); ok {
+				pass.ExportObjectFact(fn, c.newFact(spec))
+				changed = true
+			}
+		}
+	}
+}
+
+// forwardedSpec returns the spec for calls of the function, if it forwards its
+// trailing variadic parameter to a checked call.
+func (c *checker) forwardedSpec(pass *analysis.Pass, decl *ast.FuncDecl, fn *types.Func,
+	recvs map[recvKey]*types.TypeName) (CallSpec, bool) {
+
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() || !isEmptyInterfaceSlice(params.At(params.Len()-1).Type()) {
+		return CallSpec{}, false
+	}
+	variadic := params.At(params.Len() - 1)
+	isParam := func(expr ast.Expr, v *types.Var) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == v
+	}
+
+	var spec CallSpec
+	var found bool
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		ce, ok := n.(*ast.CallExpr)
+		if !ok || !ce.Ellipsis.IsValid() {
+			return true
+		}
+		inner, ok := c.match(pass, ce, recvs)
+		if !ok || len(ce.Args) != inner.Start+1 {
+			return true
+		}
+		// The variadic parameter is forwarded either directly, or appended
+		// to a prefix that is assumed to be well-formed.
+		last := ast.Unparen(ce.Args[len(ce.Args)-1])
+		direct := isParam(last, variadic)
+		if !direct {
+			call, ok := last.(*ast.CallExpr)
+			if !ok || !call.Ellipsis.IsValid() || len(call.Args) != 2 ||
+				!isParam(call.Args[1], variadic) || !isBuiltin(pass, call, "append") {
+				return true
+			}
+		}
+		spec = inner
+		spec.Name = fn.Name()
+		spec.Recv = ""
+		spec.Start = params.Len() - 1
+		spec.RequireCtx = inner.RequireCtx && direct
+		spec.Msg = NoMsg
+		if inner.Msg >= 0 && inner.Msg < len(ce.Args) {
+			for i := 0; i < params.Len()-1; i++ {
+				if isParam(ce.Args[inner.Msg], params.At(i)) {
+					spec.Msg = i
+				}
+			}
+		}
+		found = true
+		return false
+	})
+	return spec, found
+}
+
+// isEmptyInterfaceSlice reports whether t is a slice of empty interfaces.
+func isEmptyInterfaceSlice(t types.Type) bool {
+	slice, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	iface, ok := slice.Elem().Underlying().(*types.Interface)
+	return ok && iface.Empty()
+}
+
+// isBuiltin reports whether the call is a call of the named builtin.
+func isBuiltin(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := pass.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
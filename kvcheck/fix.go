@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// placeholder is the value that is appended to a dangling key.
+const placeholder = "nil"
+
+// stringer is the fmt.Stringer interface.
+var stringer = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "String", types.NewSignatureType(nil, nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String])), false)),
+}, nil).Complete()
+
+// parityFix suggests appending a placeholder value if the odd number of
+// key/value arguments is caused by a dangling string key.
+func parityFix(pass *analysis.Pass, spec CallSpec, kvs []ast.Expr) []analysis.SuggestedFix {
+	last := kvs[len(kvs)-1]
+	if !isKey(pass, spec, last) {
+		return nil
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Add placeholder value for key %s", render(pass.Fset, last)),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     last.End(),
+			End:     last.End(),
+			NewText: []byte(", " + placeholder),
+		}},
+	}}
+}
+
+// keyFix suggests a fix for a key that is not a string. Keys of named string
+// types and keys that implement fmt.Stringer are converted to strings.
+// Otherwise, if insert is set, the
+// argument is assumed to be a value with a missing key, and a key derived from
+// the expression is inserted. The derived key is added to taken.
+func (c *checker) keyFix(pass *analysis.Pass, spec CallSpec, key ast.Expr,
+	taken map[string]bool, insert bool) []analysis.SuggestedFix {
+
+	t := typeOf(pass, key)
+	if t == nil {
+		return nil
+	}
+	if isString(pass, key) {
+		return []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Convert key %s to string", render(pass.Fset, key)),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     key.Pos(),
+				End:     key.End(),
+				NewText: []byte("string(" + render(pass.Fset, key) + ")"),
+			}},
+		}}
+	}
+	if types.Implements(t, stringer) {
+		edit := analysis.TextEdit{
+			Pos:     key.End(),
+			End:     key.End(),
+			NewText: []byte(".String()"),
+		}
+		switch key.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.CallExpr, *ast.IndexExpr, *ast.ParenExpr:
+		default:
+			edit = analysis.TextEdit{
+				Pos:     key.Pos(),
+				End:     key.End(),
+				NewText: []byte("(" + render(pass.Fset, key) + ").String()"),
+			}
+		}
+		return []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Convert key %s to string", render(pass.Fset, key)),
+			TextEdits: []analysis.TextEdit{edit},
+		}}
+	}
+	if !insert {
+		return nil
+	}
+	name, ok := c.deriveKey(spec, keyName(key), taken)
+	if !ok {
+		return nil
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Insert key %q", name),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     key.Pos(),
+			End:     key.Pos(),
+			NewText: []byte(strconv.Quote(name) + ", "),
+		}},
+	}}
+}
+
+// insertKeyAt returns the index of the key in front of which a missing key can
+// be inserted, or -1 if there is none. A key is only inserted if the number of
+// key/value arguments is odd, and all keys are strings after the insertion.
+// Keys of named string types and keys that implement fmt.Stringer are
+// converted instead.
+func insertKeyAt(pass *analysis.Pass, spec CallSpec, kvs []ast.Expr) int {
+	if len(kvs)%2 == 0 {
+		return -1
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		t := typeOf(pass, kvs[i])
+		if t == nil || isString(pass, kvs[i]) || types.Implements(t, stringer) {
+			continue
+		}
+		if insertedKeys(pass, spec, kvs, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertedKeys reports whether all keys are strings after a key is inserted
+// in front of the argument at index i. The arguments from i on are shifted by
+// one, such that the keys before i are at even indexes and after i at odd
+// indexes.
+func insertedKeys(pass *analysis.Pass, spec CallSpec, kvs []ast.Expr, i int) bool {
+	for j, arg := range kvs {
+		if (j < i) == (j%2 == 0) && !isKey(pass, spec, arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// constKeys returns the constant keys of the key/value arguments.
+func constKeys(pass *analysis.Pass, kvs []ast.Expr) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < len(kvs); i += 2 {
+		if key, ok := constString(pass, kvs[i]); ok {
+			keys[key] = true
+		}
+	}
+	return keys
+}
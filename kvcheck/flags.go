@@ -0,0 +1,410 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// importPaths maps the import paths used in the specs to the import paths
+// that are treated as the respective package. It can be used as a flag.
+type importPaths struct {
+	// primary is the import path of the first spec.
+	primary string
+	paths   map[string][]string
+}
+
+func newImportPaths(specs []CallSpec) *importPaths {
+	p := &importPaths{paths: make(map[string][]string)}
+	for _, spec := range specs {
+		if p.primary == "" {
+			p.primary = spec.ImportPath
+		}
+		p.paths[spec.ImportPath] = []string{spec.ImportPath}
+	}
+	return p
+}
+
+// aliases returns the import paths that are treated as path.
+func (p *importPaths) aliases(path string) []string {
+	return p.paths[path]
+}
+
+// canonical returns the import path used in the specs for the given alias.
+func (p *importPaths) canonical(alias string) (string, bool) {
+	for path, aliases := range p.paths {
+		for _, a := range aliases {
+			if a == alias {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (p *importPaths) String() string {
+	if p == nil {
+		return ""
+	}
+	var entries []string
+	entries = append(entries, p.paths[p.primary]...)
+	var others []string
+	for path, aliases := range p.paths {
+		if path == p.primary {
+			continue
+		}
+		for _, alias := range aliases {
+			if alias != path {
+				others = append(others, path+"="+alias)
+			}
+		}
+	}
+	sort.Strings(others)
+	return strings.Join(append(entries, others...), ",")
+}
+
+// Set replaces the aliases of all packages that are mentioned in s.
+func (p *importPaths) Set(s string) error {
+	set := make(map[string][]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, alias := p.primary, entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			path, alias = entry[:i], entry[i+1:]
+		}
+		if _, ok := p.paths[path]; !ok {
+			return fmt.Errorf("unknown import path: %q", path)
+		}
+		set[path] = append(set[path], alias)
+	}
+	for path, aliases := range set {
+		p.paths[path] = aliases
+	}
+	return nil
+}
+
+// pattern is a regular expression that can be used as a flag. An empty
+// pattern is represented by a nil Regexp.
+type pattern struct {
+	*regexp.Regexp
+}
+
+func (p *pattern) String() string {
+	if p == nil || p.Regexp == nil {
+		return ""
+	}
+	return p.Regexp.String()
+}
+
+func (p *pattern) Set(s string) error {
+	if s == "" {
+		p.Regexp = nil
+		return nil
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return err
+	}
+	p.Regexp = re
+	return nil
+}
+
+// reservedKeys maps the import paths used in the specs to the keys that are
+// reserved by the respective package. It can be used as a flag.
+type reservedKeys struct {
+	keys map[string][]string
+}
+
+func newReservedKeys(specs []CallSpec) *reservedKeys {
+	r := &reservedKeys{keys: make(map[string][]string)}
+	for _, spec := range specs {
+		if _, ok := r.keys[spec.ImportPath]; !ok {
+			r.keys[spec.ImportPath] = nil
+		}
+		for _, key := range spec.ReservedKeys {
+			if !r.contains(spec.ImportPath, key) {
+				r.keys[spec.ImportPath] = append(r.keys[spec.ImportPath], key)
+			}
+		}
+	}
+	return r
+}
+
+// contains reports whether the key is reserved by the package.
+func (r *reservedKeys) contains(path, key string) bool {
+	for _, k := range r.keys[path] {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reservedKeys) String() string {
+	if r == nil {
+		return ""
+	}
+	var entries []string
+	for path, keys := range r.keys {
+		for _, key := range keys {
+			entries = append(entries, path+"="+key)
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// Set replaces the reserved keys. Bare keys replace the reserved keys of all
+// packages, keys of the form "path=key" replace the reserved keys of the
+// package with the given import path.
+func (r *reservedKeys) Set(s string) error {
+	var all []string
+	set := make(map[string][]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "=")
+		if i < 0 {
+			all = append(all, entry)
+			continue
+		}
+		path, key := entry[:i], entry[i+1:]
+		if _, ok := r.keys[path]; !ok {
+			return fmt.Errorf("unknown import path: %q", path)
+		}
+		set[path] = append(set[path], key)
+	}
+	if all != nil {
+		for path := range r.keys {
+			r.keys[path] = all
+		}
+	}
+	for path, keys := range set {
+		r.keys[path] = keys
+	}
+	return nil
+}
+
+// ruleSet is a set of rule IDs that can be used as a flag.
+type ruleSet struct {
+	prefix string
+	ids    map[string]bool
+}
+
+func (s *ruleSet) contains(id string) bool {
+	return s.ids[id]
+}
+
+func (s *ruleSet) String() string {
+	if s == nil {
+		return ""
+	}
+	var ids []string
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// Set replaces the set with the comma-separated list of rule IDs.
+func (s *ruleSet) Set(v string) error {
+	ids := make(map[string]bool)
+	for _, id := range strings.Split(v, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !strings.HasPrefix(id, s.prefix) || RuleDoc(id) == "" ||
+			len(id) != len(s.prefix)+3 {
+
+			return fmt.Errorf("unknown rule: %q", id)
+		}
+		ids[id] = true
+	}
+	s.ids = ids
+	return nil
+}
+
+// typeName is a qualified type name, e.g., "example.com/logkey.Key". It can be
+// used as a flag. The zero value is unset.
+type typeName struct {
+	path string
+	name string
+}
+
+func (n *typeName) isSet() bool {
+	return n.name != ""
+}
+
+// matches reports whether t is the named type.
+func (n *typeName) matches(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == n.path && named.Obj().Name() == n.name
+}
+
+func (n *typeName) String() string {
+	if n == nil || !n.isSet() {
+		return ""
+	}
+	return n.path + "." + n.name
+}
+
+// Set sets the qualified type name. The empty string unsets it.
+func (n *typeName) Set(s string) error {
+	if s == "" {
+		*n = typeName{}
+		return nil
+	}
+	i := strings.LastIndex(s, ".")
+	if i <= 0 || i == len(s)-1 || strings.Contains(s[i+1:], "/") {
+		return fmt.Errorf("invalid qualified type name: %q", s)
+	}
+	n.path, n.name = s[:i], s[i+1:]
+	return nil
+}
+
+// keySchema maps keys to the permitted types of their values. It is read from
+// a file and can be used as a flag that names the file. Every line of the file
+// maps a key to a comma-separated list of qualified types:
+//
+//	ia: github.com/scionproto/scion/go/lib/addr.IA
+//	duration: time.Duration
+//	port: uint16, int
+//
+// Empty lines and lines starting with # are ignored.
+type keySchema struct {
+	file  string
+	types map[string][]string
+}
+
+func (s *keySchema) isSet() bool {
+	return s.file != ""
+}
+
+func (s *keySchema) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.file
+}
+
+// Set reads the schema from the file. The empty string unsets the schema.
+func (s *keySchema) Set(file string) error {
+	if file == "" {
+		*s = keySchema{}
+		return nil
+	}
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	schema := make(map[string][]string)
+	for i, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sep := strings.Index(line, ":")
+		if sep < 0 {
+			return fmt.Errorf("%s:%d: expected \"key: type\": %q", file, i+1, line)
+		}
+		key := strings.TrimSpace(line[:sep])
+		if key == "" {
+			return fmt.Errorf("%s:%d: empty key", file, i+1)
+		}
+		if _, ok := schema[key]; ok {
+			return fmt.Errorf("%s:%d: duplicate key: %q", file, i+1, key)
+		}
+		var typs []string
+		for _, t := range strings.Split(line[sep+1:], ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				typs = append(typs, t)
+			}
+		}
+		if len(typs) == 0 {
+			return fmt.Errorf("%s:%d: no types for key: %q", file, i+1, key)
+		}
+		schema[key] = typs
+	}
+	s.file, s.types = file, schema
+	return nil
+}
+
+// allowlist is a set of keys that is read from a file. It can be used as a
+// flag that names the file. The file lists one key per line. Empty lines and
+// lines starting with # are ignored.
+type allowlist struct {
+	file string
+	keys map[string]bool
+}
+
+func (a *allowlist) isSet() bool {
+	return a.file != ""
+}
+
+func (a *allowlist) contains(key string) bool {
+	return a.keys[key]
+}
+
+func (a *allowlist) String() string {
+	if a == nil {
+		return ""
+	}
+	return a.file
+}
+
+// Set reads the keys from the file. The empty string unsets the allowlist.
+func (a *allowlist) Set(file string) error {
+	if file == "" {
+		*a = allowlist{}
+		return nil
+	}
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]bool)
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys[line] = true
+	}
+	a.file, a.keys = file, keys
+	return nil
+}
@@ -0,0 +1,393 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// verbRE matches printf verbs. The space flag is not supported, such that
+// messages like "100% done" are not mistaken for a format string.
+var verbRE = regexp.MustCompile(verbPattern)
+
+// labeledVerbRE matches printf verbs including a preceding label, e.g.,
+// "paths=%d" or "paths: %d". The label is captured.
+var labeledVerbRE = regexp.MustCompile(`(?:(\w+)(?:=|: ?))?` + verbPattern)
+
+const verbPattern = `%[-+#0]*(\[\d+\])?(\d+|\*)?(\.(\d+|\*)?)?(\[\d+\])?[vTtbcdoOqxXUeEfFgGspw]`
+
+// formatVerbs returns the printf verbs in the message.
+func formatVerbs(msg string) []string {
+	var verbs []string
+	for _, part := range strings.Split(msg, "%%") {
+		verbs = append(verbs, verbRE.FindAllString(part, -1)...)
+	}
+	return verbs
+}
+
+// checkFormat reports constant messages that contain printf verbs. It reports
+// whether a diagnostic was reported.
+//
+// If the message is a string literal and every verb has a matching argument,
+// a fix that turns the arguments into key/value pairs is suggested.
+func (c *checker) checkFormat(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec) bool {
+	if spec.Msg < 0 || spec.Msg >= len(ce.Args) {
+		return false
+	}
+	arg := ce.Args[spec.Msg]
+	msg, ok := constString(pass, arg)
+	if !ok {
+		return false
+	}
+	verbs := formatVerbs(msg)
+	if len(verbs) == 0 {
+		return false
+	}
+	diag := analysis.Diagnostic{
+		Pos:      arg.Pos(),
+		End:      arg.End(),
+		Category: ruleFormatVerbs,
+		Message: fmt.Sprintf("message should not contain format verbs: verbs=[%s] expr=%q",
+			strings.Join(verbs, ","), render(pass.Fset, ce)),
+	}
+	if fix, ok := c.formatFix(pass, ce, spec, verbs); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+	}
+	pass.Report(diag)
+	return true
+}
+
+// formatFix creates a fix that removes the verbs from the message and turns
+// the formatted arguments into key/value pairs. The label of a verb, e.g.,
+// "paths" in "paths=%d", is used as key of its argument.
+func (c *checker) formatFix(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec,
+	verbs []string) (analysis.SuggestedFix, bool) {
+
+	lit, ok := ce.Args[spec.Msg].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING || ce.Ellipsis != token.NoPos {
+		return analysis.SuggestedFix{}, false
+	}
+	if len(ce.Args)-spec.Start != len(verbs) {
+		return analysis.SuggestedFix{}, false
+	}
+	for _, verb := range verbs {
+		if strings.ContainsAny(verb, "[*") {
+			return analysis.SuggestedFix{}, false
+		}
+	}
+	msg, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return analysis.SuggestedFix{}, false
+	}
+	msg, labels := splitFormat(msg)
+	edits := []analysis.TextEdit{{
+		Pos:     lit.Pos(),
+		End:     lit.End(),
+		NewText: []byte(strconv.Quote(msg)),
+	}}
+	if args := ce.Args[spec.Start:]; len(args) > 0 {
+		ctx, ok := c.keyValueCtx(pass.Fset, spec, args, labels, nil)
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		edits = append(edits, analysis.TextEdit{
+			Pos:     args[0].Pos(),
+			End:     args[len(args)-1].End(),
+			NewText: []byte(ctx),
+		})
+	}
+	return analysis.SuggestedFix{
+		Message:   "Move formatted arguments to the key/value context",
+		TextEdits: edits,
+	}, true
+}
+
+// checkSprint reports messages that are formatted with fmt.Sprintf or
+// fmt.Sprint. A fix that replaces the formatted message with a constant
+// message and moves the formatted arguments to the key/value context is
+// suggested, if possible.
+func (c *checker) checkSprint(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec) {
+	if spec.Msg < 0 || spec.Msg >= len(ce.Args) {
+		return
+	}
+	call, ok := ast.Unparen(ce.Args[spec.Msg]).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	name := sprintFunc(pass, call)
+	if name == "" {
+		return
+	}
+	diag := analysis.Diagnostic{
+		Pos:      call.Pos(),
+		End:      call.End(),
+		Category: ruleSprint,
+		Message: fmt.Sprintf("message should be constant, use key/value context instead of "+
+			"fmt.%s: expr=%q", name, render(pass.Fset, ce)),
+	}
+	if fix, ok := c.sprintFix(pass, ce, spec, call, name); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+	}
+	pass.Report(diag)
+}
+
+// sprintFunc returns the name of the function if the call is fmt.Sprintf or
+// fmt.Sprint. Otherwise, the empty string is returned.
+func sprintFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	se, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	id, ok := se.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	pkg, ok := pass.TypesInfo.Uses[id].(*types.PkgName)
+	if !ok || pkg.Imported().Path() != "fmt" {
+		return ""
+	}
+	switch se.Sel.Name {
+	case "Sprintf", "Sprint":
+		return se.Sel.Name
+	}
+	return ""
+}
+
+// sprintFix creates a fix that replaces the formatted message with a constant
+// message, and moves the formatted arguments to the key/value context.
+func (c *checker) sprintFix(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec,
+	call *ast.CallExpr, name string) (analysis.SuggestedFix, bool) {
+
+	if ce.Ellipsis != token.NoPos || call.Ellipsis != token.NoPos || len(ce.Args) < spec.Start {
+		return analysis.SuggestedFix{}, false
+	}
+	var msg string
+	var args []ast.Expr
+	var labels []string
+	switch name {
+	case "Sprintf":
+		if len(call.Args) == 0 {
+			return analysis.SuggestedFix{}, false
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return analysis.SuggestedFix{}, false
+		}
+		format, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return analysis.SuggestedFix{}, false
+		}
+		verbs := formatVerbs(format)
+		if len(verbs) != len(call.Args)-1 {
+			return analysis.SuggestedFix{}, false
+		}
+		for _, verb := range verbs {
+			if strings.ContainsAny(verb, "[*") {
+				return analysis.SuggestedFix{}, false
+			}
+		}
+		msg, labels = splitFormat(format)
+		args = call.Args[1:]
+	case "Sprint":
+		var parts []string
+		for _, arg := range call.Args {
+			if s, ok := constString(pass, arg); ok {
+				parts = append(parts, s)
+				continue
+			}
+			args = append(args, arg)
+		}
+		msg = strings.Trim(strings.Join(strings.Fields(strings.Join(parts, " ")), " "), " :,=")
+	}
+
+	edits := []analysis.TextEdit{{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		NewText: []byte(strconv.Quote(msg)),
+	}}
+	if len(args) > 0 {
+		taken := make(map[string]bool)
+		for _, arg := range ce.Args[spec.Start:] {
+			if key, ok := constString(pass, arg); ok {
+				taken[key] = true
+			}
+		}
+		ctx, ok := c.keyValueCtx(pass.Fset, spec, args, labels, taken)
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		if len(ce.Args) > spec.Start {
+			edits = append(edits, analysis.TextEdit{
+				Pos:     ce.Args[spec.Start].Pos(),
+				End:     ce.Args[spec.Start].Pos(),
+				NewText: []byte(ctx + ", "),
+			})
+		} else {
+			last := ce.Args[len(ce.Args)-1]
+			edits = append(edits, analysis.TextEdit{
+				Pos:     last.End(),
+				End:     last.End(),
+				NewText: []byte(", " + ctx),
+			})
+		}
+	}
+	return analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Replace fmt.%s with key/value context", name),
+		TextEdits: edits,
+	}, true
+}
+
+// splitFormat removes the printf verbs and their labels from the format. It
+// returns the remaining message, and the label of every verb or the empty
+// string for verbs without a label. Separators that are left dangling by the
+// removed verbs are dropped, e.g., "count %d/%d" becomes "count". If nothing
+// but the labels remains, the labels are kept as message.
+func splitFormat(format string) (string, []string) {
+	var labels []string
+	parts := strings.Split(format, "%%")
+	labeled := make([]string, len(parts))
+	for i, part := range parts {
+		labeled[i] = labeledVerbRE.ReplaceAllStringFunc(part, func(verb string) string {
+			label := labeledVerbRE.FindStringSubmatch(verb)[1]
+			labels = append(labels, label)
+			return label + verbMarker
+		})
+		parts[i] = labeledVerbRE.ReplaceAllString(part, verbMarker)
+	}
+	if msg := dropVerbs(strings.Join(parts, "%")); msg != "" {
+		return msg, labels
+	}
+	return dropVerbs(strings.Join(labeled, "%")), labels
+}
+
+// verbMarker replaces the verbs in the format, such that the words that
+// contained a verb can be identified.
+const verbMarker = "\x00"
+
+// dropVerbs removes the verb markers from the format. Words that only consist
+// of separators after the marker is removed are dropped.
+func dropVerbs(format string) string {
+	var words []string
+	for _, word := range strings.Fields(format) {
+		if strings.Contains(word, verbMarker) {
+			word = strings.ReplaceAll(word, verbMarker, "")
+			if strings.IndexFunc(word, isWordRune) < 0 {
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+	return strings.Trim(strings.Join(words, " "), " :,=")
+}
+
+// isWordRune reports whether the rune is a letter or a digit.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// keyValueCtx renders the arguments as key/value pairs. The keys are derived
+// from the labels, if there is one for the argument, or from the arguments
+// themselves. The derived keys are distinct from each other and from the taken
+// keys. The boolean is false if no valid key can be derived for an argument.
+func (c *checker) keyValueCtx(fset *token.FileSet, spec CallSpec, args []ast.Expr,
+	labels []string, taken map[string]bool) (string, bool) {
+
+	seen := make(map[string]bool)
+	for key := range taken {
+		seen[key] = true
+	}
+	var ctx []string
+	for i, arg := range args {
+		name := keyName(arg)
+		if i < len(labels) && labels[i] != "" {
+			name = labels[i]
+		}
+		key, ok := c.deriveKey(spec, name, seen)
+		if !ok {
+			return "", false
+		}
+		ctx = append(ctx, strconv.Quote(key), render(fset, arg))
+	}
+	return strings.Join(ctx, ", "), true
+}
+
+// deriveKey turns the name into a key that matches the key pattern, is not
+// reserved by the package of the spec, and is not in seen. If the name does
+// not yield such a key, "value" is used instead. The key is added to seen. The
+// boolean is false if no valid key can be derived.
+func (c *checker) deriveKey(spec CallSpec, name string, seen map[string]bool) (string, bool) {
+	// Every seen or reserved key rules out at most one candidate per name.
+	candidates := len(seen) + len(c.reserved.keys[spec.ImportPath]) + 1
+	for _, base := range []string{snakeCase(name), "value"} {
+		if base == "" {
+			continue
+		}
+		for i := 1; i <= candidates; i++ {
+			key := base
+			if i > 1 {
+				key = fmt.Sprintf("%s_%d", base, i)
+			}
+			if seen[key] || c.reserved.contains(spec.ImportPath, key) ||
+				c.keyPattern.Regexp != nil && !c.keyPattern.MatchString(key) {
+				continue
+			}
+			seen[key] = true
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// keyName derives a snake_case key from the expression, e.g., "isd_as" for
+// s.isdAS or s.isdAS.String(). An empty string is returned if no name can be
+// derived.
+func keyName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return snakeCase(e.Name)
+	case *ast.SelectorExpr:
+		return snakeCase(e.Sel.Name)
+	case *ast.CallExpr:
+		if se, ok := e.Fun.(*ast.SelectorExpr); ok && len(e.Args) == 0 {
+			return keyName(se.X)
+		}
+	case *ast.StarExpr:
+		return keyName(e.X)
+	case *ast.UnaryExpr:
+		return keyName(e.X)
+	case *ast.ParenExpr:
+		return keyName(e.X)
+	case *ast.IndexExpr:
+		return keyName(e.X)
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// KeyUse is the use of a constant key in a checked call.
+type KeyUse struct {
+	// Key is the value of the key.
+	Key string
+	// Type is the fully qualified type of the value, e.g., "time.Duration".
+	Type string
+	// Pos is the position of the key in the form "file:line:column".
+	Pos string
+}
+
+// KeyUses returns the key uses recorded in the package fact, if it is a key
+// use fact of an analyzer created by this package. The facts are exported if
+// the keyuses flag of the analyzer is set.
+func KeyUses(fact analysis.Fact) ([]KeyUse, bool) {
+	f, ok := fact.(interface{ keyUses() []KeyUse })
+	if !ok {
+		return nil, false
+	}
+	return f.keyUses(), true
+}
+
+// keyedUsesFact is the key use fact of the analyzers created with key K.
+type keyedUsesFact[K any] struct {
+	Uses []KeyUse
+}
+
+func (*keyedUsesFact[K]) AFact() {}
+
+func (f *keyedUsesFact[K]) keyUses() []KeyUse {
+	return f.Uses
+}
+
+func (f *keyedUsesFact[K]) String() string {
+	return fmt.Sprintf("keyuses(n=%d)", len(f.Uses))
+}
+
+// keyUses returns the uses of the constant keys whose values have a known
+// type.
+func keyUses(pass *analysis.Pass, kvs []ast.Expr) []KeyUse {
+	var uses []KeyUse
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok {
+			continue
+		}
+		t := typeOf(pass, kvs[i+1])
+		if t == nil {
+			continue
+		}
+		uses = append(uses, KeyUse{
+			Key:  key,
+			Type: qualifiedType(t),
+			Pos:  pass.Fset.Position(kvs[i].Pos()).String(),
+		})
+	}
+	return uses
+}
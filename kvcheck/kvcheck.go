@@ -0,0 +1,699 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package kvcheck provides an analyzer for functions and methods that take
+// alternating key/value pairs as trailing variadic arguments.
+//
+// The checked calls are described declaratively by a list of CallSpecs. For
+// every matching call, the analyzer reports an odd number of key/value
+// arguments and keys that are not strings. Functions and interface methods
+// outside of the specs can opt in with a //gochecks:kvargs directive.
+//
+// Diagnostics are suppressed by a "//nolint:<analyzer> // reason" or a
+// "//gochecks:ignore <analyzer>[,<analyzer>] reason" comment on the same or the
+// preceding line. Suppressions without a reason, or that do not suppress
+// anything, are reported.
+//
+// Packages with errors are analyzed, too. Calls with syntax errors are
+// skipped, and so are keys whose type is unknown. If attributes are allowed
+// in the context, calls with arguments of unknown type are skipped entirely.
+package kvcheck
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// CallSpec describes a function or method that takes key/value pairs as
+// trailing variadic arguments.
+type CallSpec struct {
+	// ImportPath is the import path of the package that declares the function
+	// or the receiver type.
+	ImportPath string
+	// Recv is the name of the receiver type. If it names an interface, method
+	// calls on all types that implement the interface are matched. If it is
+	// empty, the spec describes a package-level function.
+	Recv string
+	// Name is the name of the function or method.
+	Name string
+	// Msg is the index of the message argument. NoMsg indicates that the
+	// function or method does not take a message.
+	Msg int
+	// Start is the index of the first key/value argument.
+	Start int
+	// RequireCtx indicates that the call must have at least one key/value
+	// argument.
+	RequireCtx bool
+	// AttrTypes lists the qualified names of types, e.g. "log/slog.Attr",
+	// whose values form a complete key/value pair on their own when they are
+	// in key position. The name "error" matches all types that implement
+	// the error interface.
+	AttrTypes []string
+	// ExactStringKeys indicates that the package only treats arguments of
+	// type string as keys, e.g., because it checks them with a type
+	// assertion. Keys of named string types are reported.
+	ExactStringKeys bool
+	// ReservedKeys lists the keys that have a special meaning for the
+	// package, e.g., because they are used in the rendered output. The
+	// reserved keys of all specs with the same import path are combined.
+	ReservedKeys []string
+}
+
+// NoMsg is used as CallSpec.Msg for functions and methods that do not take a
+// message.
+const NoMsg = -1
+
+// NewAnalyzer creates an analyzer that checks all calls matching one of the
+// specs.
+//
+// The analyzer accepts the importpaths flag that lists the import paths that
+// are treated as the package of the first spec. Entries of the form
+// "path=alias" register aliases for the packages of the other specs. The
+// keypattern flag sets the regular expression that constant keys must match.
+// The reservedkeys flag replaces the reserved keys of the specs. The keytype
+// and keyfile flags enable the key registry: constant keys must be constants of
+// the key type, or be listed in the key file. The keyschema flag names a file
+// that maps keys to the permitted types of their values. The keyuses flag
+// exports the keys and value types of all checked calls as a package fact, see
+// KeyUses. Similarly, the catalog flag exports the checked calls, see Calls. The
+// disable flag lists the IDs of the rules that are not reported.
+//
+// The diagnostics carry the stable ID of their rule as category, e.g.,
+// "LOG001" for the first rule of an analyzer with the rule prefix "LOG".
+//
+// The key type K distinguishes the facts of the analyzer from the facts of
+// other analyzers created by this package. Analyzers that run in the same
+// driver must use distinct key types, e.g., an unexported type of the package
+// that declares the analyzer. Instances of the same analyzer share the key.
+func NewAnalyzer[K any](name, prefix, doc string, specs []CallSpec) *analysis.Analyzer {
+	c := &checker{
+		name:   name,
+		prefix: prefix,
+		newFact: func(spec CallSpec) wrapperFact {
+			return &keyedWrapperFact[K]{Spec: spec}
+		},
+		newUsesFact: func(uses []KeyUse) analysis.Fact {
+			return &keyedUsesFact[K]{Uses: uses}
+		},
+		newCallsFact: func(calls []Call) analysis.Fact {
+			return &keyedCallsFact[K]{Calls: calls}
+		},
+		specs:      specs,
+		paths:      newImportPaths(specs),
+		keyPattern: &pattern{Regexp: regexp.MustCompile(DefaultKeyPattern)},
+		reserved:   newReservedKeys(specs),
+		disabled:   &ruleSet{prefix: prefix},
+		keyType:    &typeName{},
+		allowlist:  &allowlist{},
+		schema:     &keySchema{},
+	}
+	a := &analysis.Analyzer{
+		Name:             name,
+		Doc:              doc,
+		URL:              DocURL,
+		Run:              c.run,
+		RunDespiteErrors: true,
+		FactTypes: []analysis.Fact{new(keyedWrapperFact[K]), new(keyedUsesFact[K]),
+			new(keyedCallsFact[K])},
+	}
+	a.Flags.Var(c.paths, "importpaths", fmt.Sprintf("comma-separated list of import paths "+
+		"treated as %q, use path=alias for other packages", c.paths.primary))
+	a.Flags.Var(c.keyPattern, "keypattern",
+		"regular expression that constant keys must match, empty to disable")
+	a.Flags.Var(c.reserved, "reservedkeys", "comma-separated list of reserved keys "+
+		"for all packages, use path=key to set the reserved keys of a single package")
+	a.Flags.Var(c.keyType, "keytype", "qualified name of the type that constant keys must "+
+		"have, e.g., example.com/logkey.Key, empty to disable")
+	a.Flags.Var(c.allowlist, "keyfile", "file with the keys that are allowed in addition "+
+		"to the constants of the key type, one per line, empty to disable")
+	a.Flags.Var(c.schema, "keyschema", "file that maps keys to the permitted types of "+
+		"their values, one \"key: type[, type]\" per line, empty to disable")
+	a.Flags.BoolVar(&c.recordUses, "keyuses", false, "export the keys and value types of "+
+		"the checked calls as package facts")
+	a.Flags.BoolVar(&c.recordCalls, "catalog", false, "export the checked calls with their "+
+		"messages and keys as package facts")
+	a.Flags.Var(c.disabled, "disable", fmt.Sprintf("comma-separated list of rule IDs "+
+		"that are not reported, e.g., %s001", prefix))
+	return a
+}
+
+// DefaultKeyPattern is the default regular expression that constant keys must
+// match. It enforces snake_case keys.
+const DefaultKeyPattern = `^[a-z][a-z0-9_]*$`
+
+type checker struct {
+	name         string
+	prefix       string
+	newFact      func(spec CallSpec) wrapperFact
+	specs        []CallSpec
+	paths        *importPaths
+	keyPattern   *pattern
+	reserved     *reservedKeys
+	disabled     *ruleSet
+	keyType      *typeName
+	allowlist    *allowlist
+	schema       *keySchema
+	recordUses   bool
+	newUsesFact  func(uses []KeyUse) analysis.Fact
+	recordCalls  bool
+	newCallsFact func(calls []Call) analysis.Fact
+}
+
+// recvKey identifies a receiver type of a call spec.
+type recvKey struct {
+	importPath string
+	name       string
+}
+
+func (c *checker) run(pass *analysis.Pass) (interface{}, error) {
+	pass = c.withRules(pass)
+	pkgs := importedPackages(pass.Pkg)
+	recvs := c.resolveRecvs(pkgs)
+	st := &packageState{registry: c.resolveRegistry(pass.Pkg, pkgs)}
+	sups := newSuppressions(pass, c.name)
+	c.exportDirectives(sups.filter(pass, nil))
+	c.exportWrappers(pass, recvs)
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			ce, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			spec, ok := c.match(pass, ce, recvs)
+			if !ok {
+				return true
+			}
+			c.check(sups.filter(pass, ce), ce, spec, st)
+			if c.recordCalls {
+				st.calls = append(st.calls, newCall(pass, file, ce, spec))
+			}
+			return true
+		})
+	}
+	sups.report(pass, c.name)
+	if c.recordUses && len(st.uses) > 0 {
+		pass.ExportPackageFact(c.newUsesFact(st.uses))
+	}
+	if c.recordCalls && len(st.calls) > 0 {
+		pass.ExportPackageFact(c.newCallsFact(st.calls))
+	}
+	return nil, nil
+}
+
+// packageState is the state of the analysis of a single package.
+type packageState struct {
+	// registry maps the values of the registry constants to the constants.
+	registry map[string]*types.Const
+	// uses are the key uses of the checked calls, if they are recorded.
+	uses []KeyUse
+	// calls are the checked calls, if they are recorded.
+	calls []Call
+}
+
+// match returns the spec that matches the call expression. Calls of wrappers
+// are matched with the spec recorded in their fact.
+func (c *checker) match(pass *analysis.Pass, ce *ast.CallExpr,
+	recvs map[recvKey]*types.TypeName) (CallSpec, bool) {
+
+	if spec, ok := c.wrapperSpec(pass, ce); ok {
+		return spec, true
+	}
+	se, ok := ce.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return CallSpec{}, false
+	}
+	if id, ok := se.X.(*ast.Ident); ok {
+		if pkg, ok := pass.TypesInfo.Uses[id].(*types.PkgName); ok {
+			path, ok := c.paths.canonical(pkg.Imported().Path())
+			if !ok {
+				return CallSpec{}, false
+			}
+			for _, spec := range c.specs {
+				if spec.Recv == "" && spec.ImportPath == path && spec.Name == se.Sel.Name {
+					return spec, true
+				}
+			}
+			return CallSpec{}, false
+		}
+	}
+	for _, spec := range c.specs {
+		if spec.Recv == "" || spec.Name != se.Sel.Name {
+			continue
+		}
+		recv := recvs[recvKey{importPath: spec.ImportPath, name: spec.Recv}]
+		if recv != nil && isRecv(pass, se, recv) {
+			return spec, true
+		}
+	}
+	return CallSpec{}, false
+}
+
+// isRecv reports whether the selector is a method call on the receiver type.
+// For interfaces, method calls on all types that implement it are accepted.
+func isRecv(pass *analysis.Pass, se *ast.SelectorExpr, recv *types.TypeName) bool {
+	if iface, ok := recv.Type().Underlying().(*types.Interface); ok {
+		// Invalid types implement every interface.
+		t := typeOf(pass, se.X)
+		if t == nil {
+			return false
+		}
+		if types.Implements(t, iface) {
+			return true
+		}
+		_, isPtr := t.Underlying().(*types.Pointer)
+		return !isPtr && !types.IsInterface(t) && types.Implements(types.NewPointer(t), iface)
+	}
+	sel, ok := pass.TypesInfo.Selections[se]
+	if !ok || sel.Kind() != types.MethodVal {
+		return false
+	}
+	sig, ok := sel.Obj().Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+	t := sig.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Origin().Obj() == recv
+}
+
+// importedPackages returns pkg and the packages that are transitively imported
+// by it, keyed by import path.
+func importedPackages(pkg *types.Package) map[string]*types.Package {
+	pkgs := make(map[string]*types.Package)
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if _, ok := pkgs[pkg.Path()]; ok {
+			return
+		}
+		pkgs[pkg.Path()] = pkg
+		for _, imp := range pkg.Imports() {
+			visit(imp)
+		}
+	}
+	visit(pkg)
+	return pkgs
+}
+
+// resolveRecvs resolves the receiver types of the specs in the given packages.
+func (c *checker) resolveRecvs(pkgs map[string]*types.Package) map[recvKey]*types.TypeName {
+	recvs := make(map[recvKey]*types.TypeName)
+	for _, spec := range c.specs {
+		if spec.Recv == "" {
+			continue
+		}
+		key := recvKey{importPath: spec.ImportPath, name: spec.Recv}
+		if _, ok := recvs[key]; ok {
+			continue
+		}
+		for _, path := range c.paths.aliases(spec.ImportPath) {
+			pkg, ok := pkgs[path]
+			if !ok {
+				continue
+			}
+			if obj, ok := pkg.Scope().Lookup(spec.Recv).(*types.TypeName); ok {
+				recvs[key] = obj
+				break
+			}
+		}
+	}
+	return recvs
+}
+
+func (c *checker) check(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec,
+	st *packageState) {
+
+	// The arguments of calls with syntax errors are the parser's best guess.
+	if hasBadExpr(ce) {
+		return
+	}
+	// Calls that confuse the key/value API with printf are only reported as
+	// such, the other diagnostics would be misleading.
+	if c.checkFormat(pass, ce, spec) {
+		return
+	}
+	c.checkSprint(pass, ce, spec)
+	if len(ce.Args) <= spec.Start {
+		if spec.RequireCtx {
+			reportf(pass, ruleMissingCtx, ce.Pos(), "should have context: expr=%q",
+				render(pass.Fset, ce))
+		}
+		return
+	}
+	varargs := ce.Args[spec.Start:]
+	if ce.Ellipsis != token.NoPos {
+		// We can only check varargs with ellipsis if the slice can be
+		// reconstructed.
+		var ok bool
+		if varargs, ok = resolveEllipsis(pass, ce); !ok {
+			return
+		}
+		if len(varargs) == 0 {
+			if spec.RequireCtx {
+				reportf(pass, ruleMissingCtx, ce.Pos(), "should have context: expr=%q",
+					render(pass.Fset, ce))
+			}
+			return
+		}
+		// The slice might be shared by multiple calls, thus, fixes are not
+		// suggested.
+		pass = withoutFixes(pass)
+	}
+	// Without types, attributes cannot be told apart from keys and values.
+	if len(spec.AttrTypes) > 0 && !allTyped(pass, varargs) {
+		return
+	}
+	kvs := keyValues(pass, varargs, spec.AttrTypes)
+	insert := insertKeyAt(pass, spec, kvs)
+	if len(kvs)%2 != 0 {
+		// For reconstructed slices, the parity is reported at the call.
+		pos := kvs[0].Pos()
+		if ce.Ellipsis != token.NoPos {
+			pos = ce.Args[len(ce.Args)-1].Pos()
+		}
+		diag := analysis.Diagnostic{
+			Pos:      pos,
+			Category: ruleParity,
+			Message: fmt.Sprintf("context should be even: len=%d ctx=%s expr=%q",
+				len(kvs), renderCtx(pass.Fset, kvs), render(pass.Fset, ce)),
+		}
+		// Inserting a missing key restores the parity on its own.
+		if insert < 0 {
+			diag.SuggestedFixes = parityFix(pass, spec, kvs)
+		}
+		pass.Report(diag)
+	}
+	taken := constKeys(pass, kvs)
+	for i := 0; i < len(kvs); i += 2 {
+		lit := kvs[i]
+		// Keys with unknown types are already reported by the type checker.
+		if typeOf(pass, lit) != nil && !isKey(pass, spec, lit) {
+			pass.Report(analysis.Diagnostic{
+				Pos:      lit.Pos(),
+				Category: ruleKeyType,
+				Message: fmt.Sprintf("key should be string: type=%q name=%q expr=%q",
+					pass.TypesInfo.TypeOf(lit), render(pass.Fset, lit), render(pass.Fset, ce)),
+				SuggestedFixes: c.keyFix(pass, spec, lit, taken, i == insert),
+			})
+		}
+	}
+	checkDuplicates(pass, ce, kvs)
+	c.checkNaming(pass, ce, kvs)
+	c.checkReserved(pass, ce, spec, kvs)
+	c.checkRegistered(pass, ce, kvs, st.registry)
+	c.checkSchema(pass, ce, kvs)
+	if c.recordUses {
+		st.uses = append(st.uses, keyUses(pass, kvs)...)
+	}
+}
+
+// hasBadExpr reports whether the node contains a syntax error.
+func hasBadExpr(node ast.Node) bool {
+	var bad bool
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.BadExpr, *ast.BadStmt, *ast.BadDecl:
+			bad = true
+		}
+		return !bad
+	})
+	return bad
+}
+
+// withoutFixes returns a copy of the pass that drops the suggested fixes of
+// all reported diagnostics.
+func withoutFixes(pass *analysis.Pass) *analysis.Pass {
+	p := *pass
+	p.Report = func(diag analysis.Diagnostic) {
+		diag.SuggestedFixes = nil
+		pass.Report(diag)
+	}
+	return &p
+}
+
+// checkReserved reports constant keys that are reserved by the package of the
+// spec.
+func (c *checker) checkReserved(pass *analysis.Pass, ce *ast.CallExpr, spec CallSpec,
+	kvs []ast.Expr) {
+
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok || !c.reserved.contains(spec.ImportPath, key) {
+			continue
+		}
+		reportf(pass, ruleReservedKey, kvs[i].Pos(), "key is reserved: key=%q expr=%q", key,
+			render(pass.Fset, ce))
+	}
+}
+
+// checkNaming reports constant keys that do not match the key pattern. If the
+// key is a string literal, a fix that rewrites it to snake_case is suggested.
+func (c *checker) checkNaming(pass *analysis.Pass, ce *ast.CallExpr, kvs []ast.Expr) {
+	if c.keyPattern.Regexp == nil {
+		return
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok || c.keyPattern.MatchString(key) {
+			continue
+		}
+		diag := analysis.Diagnostic{
+			Pos:      kvs[i].Pos(),
+			End:      kvs[i].End(),
+			Category: ruleKeyPattern,
+			Message: fmt.Sprintf("key should match pattern: key=%q pattern=%q expr=%q",
+				key, c.keyPattern, render(pass.Fset, ce)),
+		}
+		if lit, ok := kvs[i].(*ast.BasicLit); ok {
+			if fixed := snakeCase(key); c.keyPattern.MatchString(fixed) {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message: fmt.Sprintf("Rename key to %q", fixed),
+					TextEdits: []analysis.TextEdit{{
+						Pos:     lit.Pos(),
+						End:     lit.End(),
+						NewText: []byte(strconv.Quote(fixed)),
+					}},
+				}}
+			}
+		}
+		pass.Report(diag)
+	}
+}
+
+// snakeCase converts the key to snake_case. Word boundaries are non
+// alphanumeric characters and changes from lower to upper case. For example,
+// "isdAS", "ISD-AS" and "HTTPServer" are converted to "isd_as", "isd_as" and
+// "http_server".
+func snakeCase(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	sep := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			b.WriteByte('_')
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				sep()
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			sep()
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// checkDuplicates reports constant keys that occur more than once.
+func checkDuplicates(pass *analysis.Pass, ce *ast.CallExpr, kvs []ast.Expr) {
+	seen := make(map[string]ast.Expr)
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok {
+			continue
+		}
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = kvs[i]
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      kvs[i].Pos(),
+			End:      kvs[i].End(),
+			Category: ruleDuplicateKey,
+			Message: fmt.Sprintf("duplicate key: key=%q expr=%q",
+				key, render(pass.Fset, ce)),
+			Related: []analysis.RelatedInformation{{
+				Pos:     first.Pos(),
+				End:     first.End(),
+				Message: fmt.Sprintf("first use of key %q", key),
+			}},
+		})
+	}
+}
+
+// constString returns the value of a constant string expression.
+func constString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// keyValues returns the key/value arguments, such that keys are at the even
+// indices. Attributes in key position are skipped, as they form a complete
+// key/value pair on their own.
+func keyValues(pass *analysis.Pass, varargs []ast.Expr, attrTypes []string) []ast.Expr {
+	if len(attrTypes) == 0 {
+		return varargs
+	}
+	var kvs []ast.Expr
+	for i := 0; i < len(varargs); i++ {
+		if isAttr(pass, varargs[i], attrTypes) {
+			continue
+		}
+		kvs = append(kvs, varargs[i])
+		if i+1 < len(varargs) {
+			kvs = append(kvs, varargs[i+1])
+			i++
+		}
+	}
+	return kvs
+}
+
+// isAttr reports whether the expression has one of the attribute types.
+func isAttr(pass *analysis.Pass, expr ast.Expr, attrTypes []string) bool {
+	t := typeOf(pass, expr)
+	if t == nil {
+		return false
+	}
+	var name string
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil {
+		name = named.Obj().Pkg().Path() + "." + named.Obj().Name()
+	}
+	for _, attr := range attrTypes {
+		switch {
+		case attr == "error" && types.Implements(t, errorType):
+			return true
+		case attr == name && name != "":
+			return true
+		}
+	}
+	return false
+}
+
+// errorType is the error interface.
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// isKey reports whether the expression is a valid key for the spec.
+func isKey(pass *analysis.Pass, spec CallSpec, expr ast.Expr) bool {
+	if !spec.ExactStringKeys {
+		return isString(pass, expr)
+	}
+	t := typeOf(pass, expr)
+	return t != nil && (types.Identical(t, types.Typ[types.String]) ||
+		types.Identical(t, types.Typ[types.UntypedString]))
+}
+
+func isString(pass *analysis.Pass, lit ast.Expr) bool {
+	t := typeOf(pass, lit)
+	if t == nil {
+		return false
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
+
+// typeOf returns the type of the expression. Nil is returned if the type is
+// unknown or invalid, e.g., because the package has type errors.
+func typeOf(pass *analysis.Pass, expr ast.Expr) types.Type {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil || t == types.Typ[types.Invalid] {
+		return nil
+	}
+	return t
+}
+
+// allTyped reports whether the types of all expressions are known.
+func allTyped(pass *analysis.Pass, exprs []ast.Expr) bool {
+	for _, expr := range exprs {
+		if typeOf(pass, expr) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func renderCtx(fset *token.FileSet, varargs []ast.Expr) string {
+	var p []string
+	for _, arg := range varargs {
+		p = append(p, render(fset, arg))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(p, ","))
+}
+
+// render renders the node as source code. Nodes that cannot be printed, e.g.,
+// incomplete nodes of files with syntax errors, are rendered on a best-effort
+// basis.
+func render(fset *token.FileSet, x interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = renderFallback(x)
+		}
+	}()
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, x); err != nil {
+		return renderFallback(x)
+	}
+	return buf.String()
+}
+
+func renderFallback(x interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("<%T>", x)
+		}
+	}()
+	if expr, ok := x.(ast.Expr); ok && expr != nil {
+		return types.ExprString(expr)
+	}
+	return fmt.Sprintf("<%T>", x)
+}
@@ -0,0 +1,418 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/oncilla/gochecks/kvcheck"
+)
+
+const kvPkg = "example.com/kv"
+
+// testKey is the fact key of the test analyzers.
+type testKey struct{}
+
+var specs = []kvcheck.CallSpec{
+	{ImportPath: kvPkg, Name: "Report", Msg: 0, Start: 1},
+	{ImportPath: kvPkg, Name: "Annotate", Msg: kvcheck.NoMsg, Start: 1, RequireCtx: true},
+	{ImportPath: kvPkg, Recv: "Reporter", Name: "Report", Msg: 1, Start: 2},
+	{ImportPath: kvPkg, Recv: "Sink", Name: "Emit", Msg: kvcheck.NoMsg, Start: 0},
+}
+
+func Test(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	analysistest.Run(t, testdata, analyzer, "basic")
+}
+
+func TestImportPaths(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("importpaths", "example.com/fork/kv"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "fork")
+}
+
+func TestImportPathsUnknown(t *testing.T) {
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("importpaths", "example.com/other=example.com/fork"); err == nil {
+		t.Error("expected error for unknown import path")
+	}
+}
+
+func TestKeyPattern(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "naming")
+}
+
+func TestKeyPatternCustom(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("keypattern", "^[a-z][a-zA-Z0-9]*$"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "camel")
+}
+
+func TestKeyPatternDisabled(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("keypattern", ""); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "nopattern")
+}
+
+func TestKeyPatternInvalid(t *testing.T) {
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("keypattern", "("); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestReservedKeys(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("reservedkeys", "id,seq"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "reserved")
+
+	if err := analyzer.Flags.Set("reservedkeys", "example.com/other=id"); err == nil {
+		t.Error("expected error for unknown import path")
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("keypattern", "^[a-z][a-z0-9]*$"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("reservedkeys", "id"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "derive")
+}
+
+func TestEllipsis(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	analysistest.Run(t, testdata, analyzer, "ellipsis")
+}
+
+func TestDirective(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	analysistest.Run(t, testdata, analyzer, "directivelib", "directiveuse")
+}
+
+func TestSuppress(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	analysistest.Run(t, testdata, analyzer, "suppress")
+}
+
+func TestRules(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("disable", "KV007"); err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, testdata, analyzer, "rules")
+	var categories []string
+	for _, r := range results {
+		for _, diag := range r.Diagnostics {
+			categories = append(categories, diag.Category)
+			if want := kvcheck.DocURL + "#" + strings.ToLower(diag.Category); diag.URL != want {
+				t.Errorf("URL = %q, want %q", diag.URL, want)
+			}
+			if kvcheck.RuleDoc(diag.Category) == "" {
+				t.Errorf("no documentation for %q", diag.Category)
+			}
+		}
+	}
+	if got, want := strings.Join(categories, ","), "KV001,KV002"; got != want {
+		t.Errorf("categories = %q, want %q", got, want)
+	}
+
+	if err := analyzer.Flags.Set("disable", "LOG001"); err == nil {
+		t.Error("expected error for rule of other analyzer")
+	}
+}
+
+// TestBroken runs the analyzer on a corpus of packages with syntax and type
+// errors. The analyzer must not crash, and must not report diagnostics that
+// depend on unknown types.
+func TestBroken(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	analysistest.Run(t, testdata, analyzer, "broken/...")
+}
+
+// FuzzCheck runs the analyzer on arbitrary sources that may import the kv
+// stub. The seeds are the packages of TestBroken and a few valid ones. The
+// analyzer must not panic, e.g., on partial syntax trees, missing types or
+// invalid suggested fixes.
+func FuzzCheck(f *testing.F) {
+	testdata := analysistest.TestData()
+	var seeds []string
+	for _, pattern := range []string{"broken/*/*.go", "basic/*.go", "ellipsis/*.go", "derive/*.go"} {
+		files, err := filepath.Glob(filepath.Join(testdata, "src", pattern))
+		if err != nil {
+			f.Fatal(err)
+		}
+		seeds = append(seeds, files...)
+	}
+	for _, file := range seeds {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(content))
+	}
+
+	// The kv stub is loaded once. The sources are type-checked in memory
+	// against it, which is much faster than loading them with go list.
+	kv, err := packages.Load(&packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  testdata,
+		Env:  append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}, kvPkg)
+	if err != nil {
+		f.Fatal(err)
+	}
+	if packages.PrintErrors(kv) > 0 {
+		f.Fatal("kv stub contains errors")
+	}
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	for _, flag := range []string{"catalog", "keyuses"} {
+		if err := analyzer.Flags.Set(flag, "true"); err != nil {
+			f.Fatal(err)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		pkg := checkSource(kv[0], src)
+		if pkg == nil {
+			return
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("panic: %v\n%s\nsource:\n%s", r, debug.Stack(), src)
+			}
+		}()
+		// Sequentially, a panic occurs in this goroutine and is recovered.
+		_, err := checker.Analyze([]*analysis.Analyzer{analyzer}, []*packages.Package{pkg},
+			&checker.Options{Sequential: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// checkSource parses and type-checks the source as the only file of a
+// package, like go/packages does for packages with errors. Only the kv stub
+// can be imported. It returns nil if the source cannot be parsed at all.
+func checkSource(kv *packages.Package, src string) *packages.Package {
+	file, err := parser.ParseFile(kv.Fset, "fuzz.go", src, parser.ParseComments|parser.AllErrors)
+	if file == nil {
+		return nil
+	}
+	pkg := &packages.Package{
+		ID:         "fuzz",
+		Name:       file.Name.Name,
+		PkgPath:    "fuzz",
+		Fset:       kv.Fset,
+		Syntax:     []*ast.File{file},
+		Imports:    make(map[string]*packages.Package),
+		TypesSizes: kv.TypesSizes,
+		TypesInfo: &types.Info{
+			Types:        make(map[ast.Expr]types.TypeAndValue),
+			Defs:         make(map[*ast.Ident]types.Object),
+			Uses:         make(map[*ast.Ident]types.Object),
+			Implicits:    make(map[ast.Node]types.Object),
+			Instances:    make(map[*ast.Ident]types.Instance),
+			Scopes:       make(map[ast.Node]*types.Scope),
+			Selections:   make(map[*ast.SelectorExpr]*types.Selection),
+			FileVersions: make(map[*ast.File]string),
+		},
+	}
+	if err != nil {
+		pkg.Errors = append(pkg.Errors, packages.Error{Msg: err.Error(), Kind: packages.ParseError})
+		pkg.IllTyped = true
+	}
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if path != kvPkg {
+				return nil, fmt.Errorf("cannot import %q", path)
+			}
+			pkg.Imports[kvPkg] = kv
+			return kv.Types, nil
+		}),
+		Error: func(err error) {
+			pkg.TypeErrors = append(pkg.TypeErrors, err.(types.Error))
+			pkg.Errors = append(pkg.Errors, packages.Error{Msg: err.Error(), Kind: packages.TypeError})
+			pkg.IllTyped = true
+		},
+	}
+	pkg.Types, _ = conf.Check(pkg.PkgPath, kv.Fset, pkg.Syntax, pkg.TypesInfo)
+	return pkg
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func TestRegistry(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	flags := map[string]string{
+		"keytype": "example.com/kvkey.Key",
+		"keyfile": filepath.Join(testdata, "registry.keys"),
+	}
+	for name, value := range flags {
+		if err := analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "registry")
+
+	for name, value := range map[string]string{
+		"keytype": "Key",
+		"keyfile": filepath.Join(testdata, "missing.keys"),
+	} {
+		if err := analyzer.Flags.Set(name, value); err == nil {
+			t.Errorf("%s=%s: expected error", name, value)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("keyschema", filepath.Join(testdata, "schema.keys")); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "schema")
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"syntax.keys":    "ia example.com/addr.IA\n",
+		"empty.keys":     "ia:\n",
+		"duplicate.keys": "ia: uint64\nia: string\n",
+	} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := analyzer.Flags.Set("keyschema", file); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestKeyUses(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("keyuses", "true"); err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, testdata, analyzer, "keyuses")
+	var got []string
+	for _, r := range results {
+		for _, fact := range r.Facts[nil] {
+			uses, ok := kvcheck.KeyUses(fact)
+			if !ok {
+				continue
+			}
+			for _, use := range uses {
+				_, pos, _ := strings.Cut(filepath.ToSlash(use.Pos), "src/")
+				got = append(got, fmt.Sprintf("%s %s %s", use.Key, use.Type, pos))
+			}
+		}
+	}
+	want := []string{
+		"duration time.Duration keyuses/keyuses.go:39:23",
+		"duration string keyuses/keyuses.go:40:23",
+		"count int keyuses/keyuses.go:41:23",
+		"count *int keyuses/keyuses.go:42:19",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	testdata := analysistest.TestData()
+	analyzer := kvcheck.NewAnalyzer[testKey]("kvtest", "KV", "test analyzer", specs)
+	if err := analyzer.Flags.Set("catalog", "true"); err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, testdata, analyzer, "calls")
+	var got []string
+	for _, r := range results {
+		for _, fact := range r.Facts[nil] {
+			calls, ok := kvcheck.Calls(fact)
+			if !ok {
+				continue
+			}
+			for _, call := range calls {
+				var keys []string
+				for _, k := range call.Keys {
+					keys = append(keys, k.Key+":"+k.Type)
+				}
+				_, pos, _ := strings.Cut(filepath.ToSlash(call.Pos), "src/")
+				got = append(got, fmt.Sprintf("%s %s.%s %q [%s] %s", call.Function,
+					call.Recv, call.Name, call.Message, strings.Join(keys, " "), pos))
+			}
+		}
+	}
+	want := []string{
+		` .Annotate "" [init:int] calls/calls.go:30:10`,
+		`(*server).run .Report "started" [port:int name:string] calls/calls.go:36:2`,
+		`(*server).run Reporter.Report "" [key:] calls/calls.go:37:2`,
+		`server.stop .Report "stopped" [] calls/calls.go:41:2`,
+		`server.stop .Report "formatted %d" [] calls/calls.go:42:2`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,214 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// resolveRegistry returns the constants of the key type, keyed by their value.
+// Only constants that are accessible from the package of the pass are
+// included, i.e., constants of the package itself, or exported constants of an
+// imported package. If several constants have the same value, the first in
+// alphabetical order is used.
+func (c *checker) resolveRegistry(self *types.Package,
+	pkgs map[string]*types.Package) map[string]*types.Const {
+
+	if !c.keyType.isSet() {
+		return nil
+	}
+	pkg, ok := pkgs[c.keyType.path]
+	if !ok {
+		return nil
+	}
+	registry := make(map[string]*types.Const)
+	// Scope names are sorted.
+	for _, name := range pkg.Scope().Names() {
+		obj, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || !c.keyType.matches(obj.Type()) || obj.Val().Kind() != constant.String {
+			continue
+		}
+		if pkg != self && !obj.Exported() {
+			continue
+		}
+		key := constant.StringVal(obj.Val())
+		if _, ok := registry[key]; !ok {
+			registry[key] = obj
+		}
+	}
+	return registry
+}
+
+// checkRegistered reports constant keys that are neither constants of the key
+// type nor listed in the allowlist. If a constant of the key type with the
+// same value exists, a fix that replaces the key with the constant is
+// suggested.
+func (c *checker) checkRegistered(pass *analysis.Pass, ce *ast.CallExpr, kvs []ast.Expr,
+	registry map[string]*types.Const) {
+
+	if !c.keyType.isSet() && !c.allowlist.isSet() {
+		return
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok || c.allowlist.contains(key) {
+			continue
+		}
+		if c.keyType.isSet() && c.keyType.matches(pass.TypesInfo.TypeOf(kvs[i])) {
+			continue
+		}
+		diag := analysis.Diagnostic{
+			Pos:      kvs[i].Pos(),
+			End:      kvs[i].End(),
+			Category: ruleUnregisteredKey,
+			Message: fmt.Sprintf("key should be registered: key=%q expr=%q",
+				key, render(pass.Fset, ce)),
+		}
+		if obj, ok := registry[key]; ok {
+			if fix, ok := registryFix(pass, kvs[i], obj); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+			}
+		}
+		pass.Report(diag)
+	}
+}
+
+// registryFix creates a fix that replaces the key with the registry constant.
+// The package of the constant is imported, if necessary.
+func registryFix(pass *analysis.Pass, key ast.Expr,
+	obj *types.Const) (analysis.SuggestedFix, bool) {
+
+	file := enclosingFile(pass, key.Pos())
+	if file == nil {
+		return analysis.SuggestedFix{}, false
+	}
+	ref, imp, ok := qualify(pass, file, obj)
+	if !ok {
+		return analysis.SuggestedFix{}, false
+	}
+	edits := []analysis.TextEdit{{
+		Pos:     key.Pos(),
+		End:     key.End(),
+		NewText: []byte(ref),
+	}}
+	edits = append(edits, imp...)
+	return analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Replace key with %s", ref),
+		TextEdits: edits,
+	}, true
+}
+
+// qualify returns the reference to the object in the file, and the edits that
+// import its package if it is not imported yet. It fails if the package name
+// would be shadowed by another declaration.
+func qualify(pass *analysis.Pass, file *ast.File,
+	obj types.Object) (string, []analysis.TextEdit, bool) {
+
+	if obj.Pkg() == pass.Pkg {
+		return obj.Name(), nil, true
+	}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != obj.Pkg().Path() {
+			continue
+		}
+		switch {
+		case spec.Name == nil:
+			return obj.Pkg().Name() + "." + obj.Name(), nil, true
+		case spec.Name.Name == ".":
+			return obj.Name(), nil, true
+		case spec.Name.Name != "_":
+			return spec.Name.Name + "." + obj.Name(), nil, true
+		}
+	}
+	name := obj.Pkg().Name()
+	if pass.Pkg.Scope().Lookup(name) != nil || declaresImport(file, name) {
+		return "", nil, false
+	}
+	return name + "." + obj.Name(), importEdits(pass.Fset, file, obj.Pkg().Path()), true
+}
+
+// declaresImport reports whether the file imports a package with the name.
+func declaresImport(file *ast.File, name string) bool {
+	for _, spec := range file.Imports {
+		if spec.Name != nil && spec.Name.Name == name {
+			return true
+		}
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err == nil && (path == name || strings.HasSuffix(path, "/"+name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// importEdits returns the edits that add the import path to the file. The
+// import is added to the first import declaration in sorted position. A single
+// import is turned into a block.
+func importEdits(fset *token.FileSet, file *ast.File, path string) []analysis.TextEdit {
+	quoted := strconv.Quote(path)
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		if !gd.Lparen.IsValid() {
+			specs := []string{render(fset, gd.Specs[0]), quoted}
+			if gd.Specs[0].(*ast.ImportSpec).Path.Value > quoted {
+				specs[0], specs[1] = specs[1], specs[0]
+			}
+			return []analysis.TextEdit{{
+				Pos:     gd.Pos(),
+				End:     gd.End(),
+				NewText: []byte("import (\n\t" + strings.Join(specs, "\n\t") + "\n)"),
+			}}
+		}
+		for _, spec := range gd.Specs {
+			if spec.(*ast.ImportSpec).Path.Value > quoted {
+				return []analysis.TextEdit{{
+					Pos:     spec.Pos(),
+					End:     spec.Pos(),
+					NewText: []byte(quoted + "\n\t"),
+				}}
+			}
+		}
+		return []analysis.TextEdit{{
+			Pos:     gd.Rparen,
+			End:     gd.Rparen,
+			NewText: []byte("\t" + quoted + "\n"),
+		}}
+	}
+	return []analysis.TextEdit{{
+		Pos:     file.Name.End(),
+		End:     file.Name.End(),
+		NewText: []byte("\n\nimport " + quoted),
+	}}
+}
@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// DocURL is the documentation of the rules. The documentation of a single
+// rule is found at the anchor of its lower-case ID.
+const DocURL = "https://github.com/oncilla/gochecks/blob/master/docs/rules.md"
+
+// The rules that diagnostics are reported for. While checking, diagnostics
+// carry the rule name in their category. Before they are reported, it is
+// replaced with the stable ID of the rule.
+const (
+	ruleParity            = "parity"
+	ruleKeyType           = "key-type"
+	ruleMissingCtx        = "missing-context"
+	ruleFormatVerbs       = "format-verbs"
+	ruleSprint            = "sprint"
+	ruleDuplicateKey      = "duplicate-key"
+	ruleKeyPattern        = "key-pattern"
+	ruleReservedKey       = "reserved-key"
+	ruleInvalidDirective  = "invalid-directive"
+	ruleSuppressionReason = "suppression-reason"
+	ruleUnusedSuppression = "unused-suppression"
+	ruleUnregisteredKey   = "unregistered-key"
+	ruleValueType         = "value-type"
+)
+
+// rules lists the rules in the order of their IDs. The ID of a rule is the
+// rule prefix of the analyzer followed by its position in this list. Thus,
+// rules must only ever be appended.
+var rules = []struct {
+	name, doc string
+}{
+	{ruleParity, "context should have an even number of key/value arguments"},
+	{ruleKeyType, "keys should be strings"},
+	{ruleMissingCtx, "calls should have key/value context"},
+	{ruleFormatVerbs, "constant messages should not contain printf verbs"},
+	{ruleSprint, "messages should not be formatted with fmt.Sprintf or fmt.Sprint"},
+	{ruleDuplicateKey, "keys should not be repeated in a call"},
+	{ruleKeyPattern, "constant keys should match the key pattern"},
+	{ruleReservedKey, "keys should not be reserved by the package"},
+	{ruleInvalidDirective, "kvargs directives should be valid"},
+	{ruleSuppressionReason, "suppression directives should have a reason"},
+	{ruleUnusedSuppression, "suppression directives should suppress a diagnostic"},
+	{ruleUnregisteredKey, "constant keys should be registered"},
+	{ruleValueType, "values should have the type that the key schema permits"},
+}
+
+// ruleID returns the stable ID of the named rule.
+func ruleID(prefix, name string) string {
+	for i, r := range rules {
+		if r.name == name {
+			return fmt.Sprintf("%s%03d", prefix, i+1)
+		}
+	}
+	return ""
+}
+
+// RuleDoc returns the documentation of the rule with the given ID, e.g.,
+// "LOG001". The empty string is returned for unknown IDs.
+func RuleDoc(id string) string {
+	if len(id) < 3 {
+		return ""
+	}
+	var n int
+	if _, err := fmt.Sscanf(id[len(id)-3:], "%03d", &n); err != nil || n < 1 || n > len(rules) {
+		return ""
+	}
+	return rules[n-1].doc
+}
+
+// reportf reports a diagnostic for the named rule.
+func reportf(pass *analysis.Pass, rule string, pos token.Pos, format string,
+	args ...interface{}) {
+
+	pass.Report(analysis.Diagnostic{
+		Pos:      pos,
+		Category: rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// withRules returns a copy of the pass that replaces the rule names in the
+// category of the diagnostics with the stable IDs, and drops the diagnostics
+// of disabled rules.
+func (c *checker) withRules(pass *analysis.Pass) *analysis.Pass {
+	p := *pass
+	p.Report = func(diag analysis.Diagnostic) {
+		id := ruleID(c.prefix, diag.Category)
+		if c.disabled.contains(id) {
+			return
+		}
+		diag.Category = id
+		diag.URL = DocURL + "#" + strings.ToLower(id)
+		pass.Report(diag)
+	}
+	return &p
+}
@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkSchema reports values whose type is not permitted by the key schema.
+// Values of unknown type are skipped.
+func (c *checker) checkSchema(pass *analysis.Pass, ce *ast.CallExpr, kvs []ast.Expr) {
+	if !c.schema.isSet() {
+		return
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := constString(pass, kvs[i])
+		if !ok {
+			continue
+		}
+		permitted, ok := c.schema.types[key]
+		if !ok {
+			continue
+		}
+		t := typeOf(pass, kvs[i+1])
+		if t == nil {
+			continue
+		}
+		actual := qualifiedType(t)
+		if contains(permitted, actual) {
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      kvs[i+1].Pos(),
+			End:      kvs[i+1].End(),
+			Category: ruleValueType,
+			Message: fmt.Sprintf("value should have schema type: key=%q expected=%q "+
+				"actual=%q expr=%q", key, strings.Join(permitted, "|"), actual,
+				render(pass.Fset, ce)),
+		})
+	}
+}
+
+// qualifiedType returns the type qualified with full import paths, e.g.,
+// "time.Duration" or "*github.com/scionproto/scion/go/lib/addr.IA".
+func qualifiedType(t types.Type) string {
+	return types.TypeString(types.Unalias(t), nil)
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package kvcheck
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	// nolintDirective is the prefix of golangci-lint style suppressions, e.g.,
+	// "//nolint:logcheck // reason".
+	nolintDirective = "//nolint:"
+	// ignoreDirective is the prefix of the project-native suppressions, e.g.,
+	// "//gochecks:ignore logcheck,serrorscheck reason".
+	ignoreDirective = "//gochecks:ignore "
+)
+
+// suppression is a directive that suppresses the diagnostics of the analyzer.
+// A directive on a line of its own applies to the following line, a trailing
+// directive applies to its own line.
+type suppression struct {
+	comment *ast.Comment
+	reason  string
+	used    bool
+}
+
+// lineKey identifies a line in a file.
+type lineKey struct {
+	file string
+	line int
+}
+
+// suppressions holds the suppression directives that name the analyzer,
+// indexed by the line they apply to.
+type suppressions struct {
+	all    []*suppression
+	byLine map[lineKey][]*suppression
+}
+
+// newSuppressions collects the suppression directives for the named analyzer
+// in all files of the package.
+func newSuppressions(pass *analysis.Pass, name string) *suppressions {
+	s := &suppressions{byLine: make(map[lineKey][]*suppression)}
+	for _, file := range pass.Files {
+		var src []byte
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				reason, ok := parseSuppression(comment.Text, name)
+				if !ok {
+					continue
+				}
+				if src == nil && pass.ReadFile != nil {
+					src, _ = pass.ReadFile(pass.Fset.File(comment.Pos()).Name())
+				}
+				pos := pass.Fset.Position(comment.Pos())
+				line := pos.Line
+				if standalone(src, pos) {
+					line++
+				}
+				sup := &suppression{comment: comment, reason: reason}
+				s.all = append(s.all, sup)
+				key := lineKey{file: pos.Filename, line: line}
+				s.byLine[key] = append(s.byLine[key], sup)
+			}
+		}
+	}
+	return s
+}
+
+// parseSuppression returns the reason of the suppression directive, and
+// whether the comment is a suppression directive that names the analyzer.
+func parseSuppression(text, name string) (string, bool) {
+	var analyzers, reason string
+	switch {
+	case strings.HasPrefix(text, nolintDirective):
+		rest := text[len(nolintDirective):]
+		analyzers = rest
+		if i := strings.IndexAny(rest, " \t/"); i >= 0 {
+			analyzers = rest[:i]
+			rest = strings.TrimSpace(rest[i:])
+			if strings.HasPrefix(rest, "//") {
+				reason = strings.TrimSpace(rest[2:])
+			}
+		}
+	case strings.HasPrefix(text, ignoreDirective):
+		fields := strings.Fields(text[len(ignoreDirective):])
+		if len(fields) == 0 {
+			return "", false
+		}
+		analyzers, reason = fields[0], strings.Join(fields[1:], " ")
+	default:
+		return "", false
+	}
+	for _, analyzer := range strings.Split(analyzers, ",") {
+		if analyzer == name {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+// standalone reports whether the comment at pos is the first token on its
+// line.
+func standalone(src []byte, pos token.Position) bool {
+	if src == nil || pos.Offset > len(src) {
+		return false
+	}
+	start := bytes.LastIndexByte(src[:pos.Offset], '\n') + 1
+	return len(bytes.TrimSpace(src[start:pos.Offset])) == 0
+}
+
+// filter returns a copy of the pass that drops the diagnostics that are
+// suppressed. If node is not nil, a directive that applies to any line of the
+// node suppresses the diagnostics. Otherwise, the directive must apply to the
+// line of the diagnostic.
+func (s *suppressions) filter(pass *analysis.Pass, node ast.Node) *analysis.Pass {
+	p := *pass
+	p.Report = func(diag analysis.Diagnostic) {
+		start, end := diag.Pos, diag.Pos
+		if node != nil {
+			start, end = node.Pos(), node.End()
+		}
+		if s.suppress(pass.Fset, start, end) {
+			return
+		}
+		pass.Report(diag)
+	}
+	return &p
+}
+
+// suppress reports whether a directive applies to a line between start and
+// end, and marks the matching directives as used.
+func (s *suppressions) suppress(fset *token.FileSet, start, end token.Pos) bool {
+	if len(s.all) == 0 {
+		return false
+	}
+	from, to := fset.Position(start), fset.Position(end)
+	suppressed := false
+	for line := from.Line; line <= to.Line; line++ {
+		for _, sup := range s.byLine[lineKey{file: from.Filename, line: line}] {
+			sup.used = true
+			suppressed = true
+		}
+	}
+	return suppressed
+}
+
+// report reports directives without a reason and directives that did not
+// suppress any diagnostic.
+func (s *suppressions) report(pass *analysis.Pass, name string) {
+	for _, sup := range s.all {
+		if sup.reason == "" {
+			reportf(pass, ruleSuppressionReason, sup.comment.Pos(),
+				"suppression directive should have a reason: directive=%q", sup.comment.Text)
+		}
+		if !sup.used {
+			reportf(pass, ruleUnusedSuppression, sup.comment.Pos(),
+				"suppression directive does not suppress anything: analyzer=%q directive=%q",
+				name, sup.comment.Text)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package basic
+
+import (
+	"errors"
+
+	"example.com/kv"
+)
+
+var (
+	errBase = errors.New("base")
+	value   = 1
+)
+
+func functions() {
+	kv.Report("message")
+	kv.Report("message", "key", value)
+	kv.Report("message", "key")        // want `context should be even: len=1 ctx=\["key"\]`
+	kv.Report("message", value, value) // want `key should be string: type="int" name="value"`
+
+	kv.Annotate(errBase, "key", value)
+	kv.Annotate(errBase) // want `should have context:`
+}
+
+type embedded struct {
+	*kv.Reporter
+}
+
+func methods(r *kv.Reporter, e embedded) {
+	r.Report(1, "message", "key", value)
+	r.Report(1, "message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+	e.Report(1, "message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+}
+
+type sink struct{}
+
+func (sink) Emit(ctx ...interface{}) {}
+
+func interfaces(s kv.Sink, impl sink) {
+	s.Emit("key", value)
+	s.Emit("key")    // want `context should be even: len=1 ctx=\["key"\]`
+	impl.Emit("key") // want `context should be even: len=1 ctx=\["key"\]`
+}
+
+// other has the same method name as kv.Reporter.
+type other struct{}
+
+func (other) Report(severity int, msg string, ctx ...interface{}) {}
+
+func unrelated(o other) {
+	o.Report(1, "message", "key")
+}
@@ -0,0 +1,46 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package directive contains directives on functions with broken signatures.
+package directive
+
+//gochecks:kvargs start=1
+func undefinedParam(msg string, ctx ...Undefined) {} // want `invalid kvargs directive`
+
+//gochecks:kvargs start=1
+func undefinedType(msg Undefined, ctx ...interface{}) {} // want undefinedType:`kvwrapper\(msg=-1 start=1\)`
+
+//gochecks:kvargs start=x
+func invalidOption(msg string, ctx ...interface{}) {} // want `invalid kvargs directive`
+
+//gochecks:kvargs
+func (undefinedRecv) method(msg string, ctx ...interface{}) {} // want `invalid kvargs directive: err="start must be set"`
+
+type iface interface {
+	//gochecks:kvargs start=1
+	Report(msg string, ctx ...Undefined) // want `invalid kvargs directive`
+}
+
+func use(i iface) {
+	undefinedType("message", "key") // want `context should be even`
+	i.Report("message", "key")
+}
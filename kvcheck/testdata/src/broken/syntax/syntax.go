@@ -0,0 +1,41 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package syntax contains calls in a file with syntax errors.
+package syntax
+
+import "example.com/kv"
+
+var value = 1
+
+func syntax() {
+	kv.Report("message", "key", ) // want `context should be even`
+	// The parser drops the value without leaving a trace in the syntax tree.
+	kv.Report("message", "key" value) // want `context should be even`
+	kv.Report("message", "key", value
+	kv.Report("message", [ "key")
+	kv.Report("message", func( { "key" }, value)
+	kv.Report("message", "key", value, "key"]
+}
+
+func unterminated() {
+	kv.Report("message", "key",
@@ -0,0 +1,55 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package types contains calls with arguments whose types are unknown.
+package types
+
+import "example.com/kv"
+
+var value = 1
+
+func unknown() {
+	kv.Report("message", undefined, value)
+	kv.Report("message", "key", undefined)
+	kv.Report("message", undefined) // want `context should be even: len=1`
+	kv.Report("message", undefined.Field, value)
+	kv.Report("message", undefined(), value)
+	kv.Report("message", Undefined{}, value)
+	kv.Report("message", "key", value, value) // want `context should be even: len=3` `key should be string`
+	kv.Report(undefined, "key", value)
+	kv.Report("message %d", undefined)               // want `message should not contain format verbs`
+	kv.Report(fmt.Sprintf("message %d", value))      // Missing import.
+	kv.Annotate(undefined)                           // want `should have context`
+	kv.Report("message", "key", value, "key", value) // want `duplicate key`
+}
+
+func receivers(r *Undefined, s kv.Undefined, u *kv.Reporter) {
+	r.Report(1, "message", "key")
+	s.Emit("key")
+	undefined.Report(1, "message", "key")
+	u.Report(undefined, "message", "key") // want `context should be even`
+}
+
+func wrongArity() {
+	kv.Report()
+	kv.Annotate() // want `should have context`
+}
@@ -0,0 +1,50 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package wrapper contains wrappers with broken signatures and bodies.
+package wrapper
+
+import "example.com/kv"
+
+var value = 1
+
+func undefinedParam(msg string, ctx ...Undefined) {
+	kv.Report(msg, ctx...)
+}
+
+func undefinedCall(msg string, ctx ...interface{}) { // want undefinedCall:`kvwrapper\(msg=-1 start=1\)`
+	kv.Report(undefined(msg), ctx...)
+	undefined(msg, ctx...)
+}
+
+func noBody(msg string, ctx ...interface{})
+
+func (undefinedRecv) method(msg string, ctx ...interface{}) { // want method:`kvwrapper\(msg=0 start=1\)`
+	kv.Report(msg, ctx...)
+}
+
+func use() {
+	undefinedParam("message", "key")
+	undefinedCall("message", "key") // want `context should be even`
+	noBody("message", "key")
+	undefinedRecv{}.method("message", "key")
+}
@@ -0,0 +1,43 @@
+// MIT License // want package:`calls\(n=5\)`
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package calls
+
+import "example.com/kv"
+
+var (
+	msg   = "dynamic"
+	value = 1
+	_     = kv.Annotate(nil, "init", value)
+)
+
+type server[T any] struct{}
+
+func (s *server[T]) run(r *kv.Reporter) {
+	kv.Report("started", "port", value, "name", "srv")
+	r.Report(1, msg, "key", undefined)
+}
+
+func (server[T]) stop(ctx []interface{}) {
+	kv.Report("stopped", ctx...)
+	kv.Report("formatted %d", value) // want `message should not contain format verbs`
+}
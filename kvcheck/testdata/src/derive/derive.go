@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package derive
+
+import (
+	"fmt"
+
+	"example.com/kv"
+)
+
+type peer struct {
+	userName string
+	addr     string
+}
+
+func labels(p peer, n int) {
+	kv.Report("found paths=%d total=%d", n, n) // want `message should not contain format verbs`
+	kv.Report("paths=%d total=%d", n, n)       // want `message should not contain format verbs`
+	kv.Report("peer: %s", p.addr)              // want `message should not contain format verbs`
+	kv.Report("count %d/%d", n, n)             // want `message should not contain format verbs`
+	kv.Report("user %s (%d)", p.userName, n)   // want `message should not contain format verbs`
+}
+
+func reserved(p peer, n int) {
+	kv.Report("id=%d", n)                                     // want `message should not contain format verbs`
+	kv.Report(fmt.Sprintf("peer=%s", p.addr), "peer", p.addr) // want `message should be constant`
+}
+
+func noKey(n int) {
+	kv.Report("%v %v %v", n, n, n) // want `message should not contain format verbs`
+}
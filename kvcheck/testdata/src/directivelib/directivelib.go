@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package directivelib
+
+import "example.com/kv"
+
+// Reporter reports messages with key/value context.
+type Reporter interface {
+	//gochecks:kvargs start=1 msg=0 analyzers=kvtest
+	Report(msg string, ctx ...interface{}) // want Report:`kvwrapper\(msg=0 start=1\)`
+
+	//gochecks:kvargs start=0 analyzers=kvtest
+	With(ctx ...interface{}) Reporter // want With:`kvwrapper\(msg=-1 start=0\)`
+
+	// Flush is not annotated.
+	Flush(ctx ...interface{})
+
+	//gochecks:kvargs start=0 analyzers=other
+	Other(ctx ...interface{})
+
+	//gochecks:kvargs start=0
+	All(ctx ...interface{}) // want All:`kvwrapper\(msg=-1 start=0\)`
+
+	//gochecks:kvargs start=0 analyzers=
+	None(ctx ...interface{}) // want `invalid kvargs directive: err="analyzers must not be empty" func="None"`
+}
+
+// Record records a value with key/value context.
+//
+//gochecks:kvargs start=2 analyzers=kvtest
+func Record(name string, value int, ctx ...interface{}) {} // want Record:`kvwrapper\(msg=-1 start=2\)`
+
+// Forward forwards to a call checked by the analyzer.
+//
+//gochecks:kvargs start=1 msg=0
+func Forward(msg string, ctx ...interface{}) { // want Forward:`kvwrapper\(msg=0 start=1\)`
+	kv.Report(msg, ctx...)
+}
+
+// Discard does not call anything checked by the analyzer.
+//
+//gochecks:kvargs start=0
+func Discard(ctx ...interface{}) {} // want Discard:`kvwrapper\(msg=-1 start=0\)`
+
+//gochecks:kvargs msg=0 analyzers=kvtest
+func Missing(msg string, ctx ...interface{}) {} // want `invalid kvargs directive: err="start must be set" func="Missing"`
+
+//gochecks:kvargs start=1 analyzers=kvtest
+func Fixed(msg string, ctx []interface{}) {} // want `invalid kvargs directive: err="function must have a variadic empty interface parameter" func="Fixed"`
+
+//gochecks:kvargs start=1 msg=1 analyzers=kvtest
+func Late(msg string, ctx ...interface{}) {} // want `invalid kvargs directive: err="msg must be before start" func="Late"`
+
+//gochecks:kvargs start=1 level analyzers=kvtest
+func Level(msg string, ctx ...interface{}) {} // want `invalid kvargs directive: err="option \\"level\\" is not of the form key=value" func="Level"`
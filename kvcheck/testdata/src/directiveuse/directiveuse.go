@@ -0,0 +1,41 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package directiveuse
+
+import "directivelib"
+
+func use(r directivelib.Reporter) {
+	r.Report("msg", "key", 1)
+	r.Report("msg", "key")               // want `context should be even: len=1 ctx=\["key"\]`
+	r.Report("msg", 1, 2)                // want `key should be string: type="int" name="1"`
+	r.With("key")                        // want `context should be even: len=1 ctx=\["key"\]`
+	r.With("key", 1).Report("msg %d", 1) // want `message should not contain format verbs`
+	r.Flush("key")
+	r.Other("key")
+	directivelib.Record("name", 1, "key") // want `context should be even: len=1 ctx=\["key"\]`
+	directivelib.Record("name", 1, "key", 1)
+	directivelib.Forward("msg", "key") // want `context should be even: len=1 ctx=\["key"\]`
+	directivelib.Discard("key")        // want `context should be even: len=1 ctx=\["key"\]`
+	r.All("key")                       // want `context should be even: len=1 ctx=\["key"\]`
+	r.None("key")
+}
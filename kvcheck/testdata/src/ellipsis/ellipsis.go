@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ellipsis
+
+import (
+	"errors"
+
+	"example.com/kv"
+)
+
+var (
+	errBase = errors.New("base")
+	value   = 1
+)
+
+func literals() {
+	kv.Report("message", []interface{}{"key", value}...)
+	kv.Report("message", []interface{}{"key"}...)                         // want `context should be even: len=1 ctx=\["key"\]`
+	kv.Report("message", []interface{}{value, value}...)                  // want `key should be string: type="int" name="value"`
+	kv.Report("message", append([]interface{}{"key", value}, "other")...) // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+}
+
+func locals(cond bool) {
+	ctx := []interface{}{"key", value}
+	ctx = append(ctx, "other", value)
+	kv.Report("message", ctx...)
+
+	odd := []interface{}{"key", value}
+	odd = append(odd, "other")
+	kv.Report("message", odd...) // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+
+	var empty []interface{}
+	empty = append(empty, value, value) // want `key should be string: type="int" name="value"`
+	kv.Report("message", empty...)
+
+	var lit = []interface{}{"dup", value}
+	lit = append(lit, []interface{}{"dup", value}...) // want `duplicate key: key="dup"`
+	kv.Report("message", lit...)
+
+	for i := 0; i < 2; i++ {
+		loop := []interface{}{"key", value}
+		kv.Report("message", append(loop, "other")...) // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	}
+
+	base := []interface{}{"key", value}
+	derived := append(base, "other")
+	kv.Report("message", derived...) // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+
+	var none []interface{}
+	kv.Annotate(errBase, none...) // want `should have context:`
+}
+
+func unknown(cond bool, param []interface{}) {
+	kv.Report("message", param...)
+
+	conditional := []interface{}{"key"}
+	if cond {
+		conditional = append(conditional, value)
+	}
+	kv.Report("message", conditional...)
+
+	reassigned := []interface{}{"key"}
+	reassigned = param
+	kv.Report("message", reassigned...)
+
+	indexed := []interface{}{"key", value}
+	indexed[0] = value
+	kv.Report("message", indexed...)
+
+	captured := []interface{}{"key"}
+	func() { captured = append(captured, value) }()
+	kv.Report("message", captured...)
+
+	pointer := []interface{}{"key"}
+	modify(&pointer)
+	kv.Report("message", pointer...)
+
+	outer := []interface{}{"key"}
+	if cond {
+		kv.Report("message", outer...)
+	}
+
+	copied := []interface{}{value, value}
+	copy(copied, []interface{}{"key", value})
+	kv.Report("message", copied...)
+
+	passed := []interface{}{value, value}
+	mutate(passed)
+	kv.Report("message", passed...)
+
+	aliased := []interface{}{value, value}
+	alias := aliased
+	alias[0] = "key"
+	kv.Report("message", aliased...)
+
+	later := []interface{}{"key"}
+	kv.Report("message", later...)
+	later = append(later, value)
+
+	shared := []interface{}{"key"}
+	kv.Report("message", shared...)
+	kv.Report("other", shared...)
+
+	base := []interface{}{"key", value}
+	derived := base
+	base = append(base, "other")
+	kv.Report("message", derived...)
+
+	b2 := []interface{}{"key", value}
+	d2 := append(b2, "x", value)
+	b2 = append(b2, "other")
+	kv.Report("message", d2...)
+}
+
+func modify(ctx *[]interface{}) {}
+
+func mutate(ctx []interface{}) {}
@@ -0,0 +1,41 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package kv is a fork of the key/value API used to test import path aliases.
+package kv
+
+// Report reports a message with key/value context.
+func Report(msg string, ctx ...interface{}) {}
+
+// Annotate annotates err with key/value context.
+func Annotate(err error, ctx ...interface{}) error { return err }
+
+// Reporter reports messages with a severity.
+type Reporter struct{}
+
+// Report reports a message with key/value context.
+func (r *Reporter) Report(severity int, msg string, ctx ...interface{}) {}
+
+// Sink receives key/value pairs.
+type Sink interface {
+	Emit(ctx ...interface{})
+}
@@ -0,0 +1,55 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package naming
+
+import "example.com/kv"
+
+const (
+	camel         = "isdAS"
+	snake         = "isd_as"
+	typedCamel id = "typedID"
+)
+
+var value = 1
+
+func valid() {
+	kv.Report("message", "isd_as", value)
+	kv.Report("message", snake, value)
+	kv.Report("message", "path2", value)
+}
+
+func invalidLiterals() {
+	kv.Report("message", "isdAS", value)      // want `key should match pattern: key="isdAS"`
+	kv.Report("message", "ISD-AS", value)     // want `key should match pattern: key="ISD-AS"`
+	kv.Report("message", "HTTPServer", value) // want `key should match pattern: key="HTTPServer"`
+	kv.Report("message", "user ID", value)    // want `key should match pattern: key="user ID"`
+	kv.Report("message", "_private", value)   // want `key should match pattern: key="_private"`
+	kv.Report("message", "2nd", value)        // want `key should match pattern: key="2nd"`
+}
+
+func invalidConstants() {
+	kv.Report("message", camel, value)      // want `key should match pattern: key="isdAS"`
+	kv.Report("message", typedCamel, value) // want `key should match pattern: key="typedID"`
+}
+
+type id string
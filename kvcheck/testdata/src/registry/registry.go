@@ -0,0 +1,44 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"example.com/kv"
+	"example.com/kvkey"
+)
+
+const local = "isd_as"
+
+var value = 1
+
+func keys() {
+	kv.Report("message", kvkey.IsdAS, value)
+	kv.Report("message", "isd_as", value)    // want `key should be registered: key="isd_as"`
+	kv.Report("message", local, value)       // want `key should be registered: key="isd_as"`
+	kv.Report("message", kvkey.Count, value) // want `key should be registered: key="count"`
+	kv.Report("message", "private", value)   // want `key should be registered: key="private"`
+	kv.Report("message", "allowed", value)
+	kv.Report("message", "request_id", value)
+	kv.Report("message", "unknown", value) // want `key should be registered: key="unknown"`
+	kv.Report("message", kvkey.Key("dynamic"), value)
+}
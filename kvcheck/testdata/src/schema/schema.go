@@ -0,0 +1,53 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package schema
+
+import (
+	"time"
+
+	"example.com/addr"
+	"example.com/kv"
+)
+
+var (
+	ia    addr.IA
+	alias addr.Alias
+	d     time.Duration
+	port  uint16
+)
+
+func values() {
+	kv.Report("message", "ia", ia)
+	kv.Report("message", "ia", alias)
+	kv.Report("message", "ia", &ia)            // want `value should have schema type: key="ia" expected="example.com/addr.IA" actual="\*example.com/addr.IA"`
+	kv.Report("message", "ia", uint64(ia))     // want `value should have schema type: key="ia" expected="example.com/addr.IA" actual="uint64"`
+	kv.Report("message", "ia", "1-ff00:0:110") // want `actual="string"`
+	kv.Report("message", "duration", d)
+	kv.Report("message", "duration", d.Seconds()) // want `key="duration" expected="time.Duration" actual="float64"`
+	kv.Report("message", "port", port)
+	kv.Report("message", "port", 80)
+	kv.Report("message", "port", "80") // want `key="port" expected="uint16\|int" actual="string"`
+	kv.Report("message", "other", "80")
+	kv.Report("message", "ia", undefined)
+	kv.Report("message", "ia") // want `context should be even`
+}
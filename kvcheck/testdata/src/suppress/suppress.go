@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package suppress
+
+import "example.com/kv"
+
+var value = 1
+
+func trailing() {
+	kv.Report("message", "key") //nolint:kvtest // key is completed downstream
+	kv.Report("message", "key") //gochecks:ignore kvtest key is completed downstream
+	kv.Report("message", "key") //nolint:other,kvtest // listed with other linters
+	kv.Report("message", "key") //nolint:other // want `context should be even`
+	kv.Report("message", "key") //gochecks:ignore other reason // want `context should be even`
+}
+
+func preceding() {
+	//nolint:kvtest // key is completed downstream
+	kv.Report("message", "key")
+	//gochecks:ignore other,kvtest key is completed downstream
+	kv.Report("message",
+		value, value)
+	kv.Report("message", "key") // want `context should be even`
+}
+
+func rotten() {
+	// want +1 `suppression directive should have a reason: directive="//nolint:kvtest"`
+	//nolint:kvtest
+	kv.Report("message", "key")
+	// want +1 `suppression directive should have a reason` `does not suppress anything: analyzer="kvtest"`
+	//gochecks:ignore kvtest
+	kv.Report("message", "key", value)
+	// want +1 `suppression directive does not suppress anything: analyzer="kvtest" directive="//nolint:kvtest // fixed long ago"`
+	//nolint:kvtest // fixed long ago
+	kv.Report("message", "key", value)
+
+	//nolint:kvtest // applies to the next line only
+	kv.Report("message", "key")
+	kv.Report("message", "key") // want `context should be even`
+}
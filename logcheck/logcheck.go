@@ -23,151 +23,128 @@
 package logcheck
 
 import (
-	"bytes"
-	"fmt"
-	"go/ast"
-	"go/printer"
-	"go/token"
-	"go/types"
-	"strings"
-
 	"golang.org/x/tools/go/analysis"
+
+	"github.com/oncilla/gochecks/kvcheck"
 )
 
-// Analyzer checks all calls on the log package.
-var Analyzer = &analysis.Analyzer{
-	Name:             "logcheck",
-	Doc:              "reports invalid log calls",
-	Run:              run,
-	RunDespiteErrors: true,
-}
+const (
+	// logPkg is the import path of the log package.
+	logPkg = "github.com/scionproto/scion/go/lib/log"
+	// slogPkg is the import path of the structured logging package of the
+	// standard library.
+	slogPkg = "log/slog"
+	// zapPkg is the import path of the zap logging package.
+	zapPkg = "go.uber.org/zap"
+	// logrPkg is the import path of the logr logging package.
+	logrPkg = "github.com/go-logr/logr"
+)
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	for _, file := range pass.Files {
-		tgtPkg := findPkgName(file)
-		if tgtPkg == "" {
-			continue
-		}
-		ast.Inspect(file, func(n ast.Node) bool {
-			ce, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
-			se, ok := ce.Fun.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
-			if !isTarget(se, tgtPkg) {
-				return true
-			}
-			var varargs []ast.Expr
-			switch se.Sel.Name {
-			case "Trace", "Debug", "Info", "Warn", "Error", "Crit":
-				if len(ce.Args) < 2 {
-					return true
-				}
-				varargs = ce.Args[1:]
-			}
-			// We cannot check if varargs with ellipsis.
-			if ce.Ellipsis != token.NoPos {
-				return true
-			}
-			if len(varargs)%2 != 0 {
-				pass.Reportf(varargs[0].Pos(), "context should be even: len=%d ctx=%s expr=%q",
-					len(varargs), renderCtx(pass.Fset, varargs), render(pass.Fset, ce))
-			}
-			for i := 0; i < len(varargs); i += 2 {
-				lit := varargs[i]
-				if !isString(pass, lit) {
-					pass.Reportf(lit.Pos(), "key should be string: type=%q name=%q expr=%q",
-						pass.TypesInfo.TypeOf(lit), render(pass.Fset, lit), render(pass.Fset, ce))
-				}
-			}
-			return true
-		})
+var (
+	// slogAttr is the type of slog attributes. They occupy a single argument.
+	slogAttr = []string{slogPkg + ".Attr"}
+	// zapField are the types that occupy a single argument when passed to a
+	// sugared zap logger: fields, and errors that are logged without a key.
+	zapField = []string{zapPkg + "/zapcore.Field", "error"}
+	// reservedKeys are the keys that the loggers use in their output by
+	// default.
+	reservedKeys = map[string][]string{
+		logPkg:  {"t", "lvl", "msg"},
+		slogPkg: {"time", "level", "msg", "source"},
+		zapPkg:  {"ts", "level", "logger", "caller", "msg", "stacktrace"},
+		logrPkg: {"logger", "level", "msg", "error", "caller"},
 	}
-	return nil, nil
-}
+)
 
-func isTarget(se *ast.SelectorExpr, tgtPkg string) bool {
-	switch x := se.X.(type) {
-	case *ast.Ident:
-		if x.Name == tgtPkg && x.Obj == nil {
-			return true
-		}
-		if x.Obj == nil {
-			return false
-		}
-		decl, ok := x.Obj.Decl.(*ast.AssignStmt)
-		if !ok {
-			return false
-		}
-		for _, data := range decl.Rhs {
-			if loggerConstructor(data, tgtPkg) {
-				return true
-			}
-		}
-		return false
-	case *ast.CallExpr:
-		return loggerConstructor(x, tgtPkg)
-	default:
-		return false
-	}
+// Analyzer checks all calls on the log package and the structured loggers of
+// log/slog, zap and logr.
+var Analyzer = NewAnalyzer()
+
+// factKey distinguishes the facts of the analyzer from the facts of other
+// analyzers built on kvcheck.
+type factKey struct{}
+
+// NewAnalyzer creates an instance of the analyzer with its own flags.
+func NewAnalyzer() *analysis.Analyzer {
+	return kvcheck.NewAnalyzer[factKey]("logcheck", "LOG", "reports invalid log calls", specs())
 }
 
-func loggerConstructor(exp ast.Expr, tgtPkg string) bool {
-	ce, ok := exp.(*ast.CallExpr)
-	if !ok {
-		return false
-	}
-	se, ok := ce.Fun.(*ast.SelectorExpr)
-	if !ok {
-		return false
+func specs() []kvcheck.CallSpec {
+	var specs []kvcheck.CallSpec
+	for _, name := range []string{"Trace", "Debug", "Info", "Warn", "Error", "Crit"} {
+		specs = append(specs,
+			kvcheck.CallSpec{ImportPath: logPkg, Name: name, Msg: 0, Start: 1},
+			kvcheck.CallSpec{ImportPath: logPkg, Recv: "Logger", Name: name, Msg: 0, Start: 1},
+		)
 	}
-	id, ok := se.X.(*ast.Ident)
-	if !ok {
-		return false
-	}
-	if id.Name != tgtPkg {
-		return false
+	// New creates a logger, or a child logger, with the given context.
+	specs = append(specs,
+		kvcheck.CallSpec{ImportPath: logPkg, Name: "New", Msg: kvcheck.NoMsg, Start: 0},
+		kvcheck.CallSpec{ImportPath: logPkg, Recv: "Logger", Name: "New", Msg: kvcheck.NoMsg,
+			Start: 0},
+	)
+	slogSpec := func(recv, name string, msg, start int) kvcheck.CallSpec {
+		return kvcheck.CallSpec{
+			ImportPath: slogPkg,
+			Recv:       recv,
+			Name:       name,
+			Msg:        msg,
+			Start:      start,
+			AttrTypes:  slogAttr,
+			// log/slog treats arguments of named string types as
+			// values with a missing key.
+			ExactStringKeys: true,
+		}
 	}
-	switch se.Sel.Name {
-	case "FromCtx", "New", "Root":
-		return true
+	for _, recv := range []string{"", "Logger"} {
+		for _, name := range []string{"Debug", "Info", "Warn", "Error"} {
+			specs = append(specs,
+				slogSpec(recv, name, 0, 1),
+				slogSpec(recv, name+"Context", 1, 2),
+			)
+		}
+		specs = append(specs,
+			slogSpec(recv, "Log", 2, 3),
+			slogSpec(recv, "With", kvcheck.NoMsg, 0),
+		)
 	}
-	return false
-}
+	specs = append(specs, slogSpec("", "Group", kvcheck.NoMsg, 1))
 
-func findPkgName(file *ast.File) string {
-	var tgtPkg string
-	for _, imp := range file.Imports {
-		if imp.Path.Value == `"github.com/scionproto/scion/go/lib/log"` {
-			tgtPkg = "log"
-			if imp.Name != nil {
-				tgtPkg = imp.Name.Name
-			}
+	zapSpec := func(name string, msg, start int) kvcheck.CallSpec {
+		return kvcheck.CallSpec{
+			ImportPath: zapPkg,
+			Recv:       "SugaredLogger",
+			Name:       name,
+			Msg:        msg,
+			Start:      start,
+			AttrTypes:  zapField,
+			// The sugared logger drops keys that are not of type
+			// string.
+			ExactStringKeys: true,
 		}
 	}
-	return tgtPkg
-}
+	for _, name := range []string{"Debugw", "Infow", "Warnw", "Errorw", "DPanicw", "Panicw",
+		"Fatalw"} {
 
-func isString(pass *analysis.Pass, lit ast.Expr) bool {
-	t, ok := pass.TypesInfo.TypeOf(lit).Underlying().(*types.Basic)
-	return ok && t.Info()&types.IsString != 0
-}
-
-func renderCtx(fset *token.FileSet, varargs []ast.Expr) string {
-	var p []string
-	for _, arg := range varargs {
-		p = append(p, render(fset, arg))
+		specs = append(specs, zapSpec(name, 0, 1))
 	}
-	return fmt.Sprintf("[%s]", strings.Join(p, ","))
-}
+	specs = append(specs,
+		zapSpec("Logw", 1, 2),
+		zapSpec("With", kvcheck.NoMsg, 0),
+		zapSpec("WithLazy", kvcheck.NoMsg, 0),
+	)
 
-func render(fset *token.FileSet, x interface{}) string {
-	var buf bytes.Buffer
-	if err := printer.Fprint(&buf, fset, x); err != nil {
-		panic(err)
+	// The logr sinks treat keys that are not of type string as invalid.
+	specs = append(specs,
+		kvcheck.CallSpec{ImportPath: logrPkg, Recv: "Logger", Name: "Info", Msg: 0, Start: 1,
+			ExactStringKeys: true},
+		kvcheck.CallSpec{ImportPath: logrPkg, Recv: "Logger", Name: "Error", Msg: 1, Start: 2,
+			ExactStringKeys: true},
+		kvcheck.CallSpec{ImportPath: logrPkg, Recv: "Logger", Name: "WithValues",
+			Msg: kvcheck.NoMsg, Start: 0, ExactStringKeys: true},
+	)
+	for i := range specs {
+		specs[i].ReservedKeys = reservedKeys[specs[i].ImportPath]
 	}
-	return buf.String()
+	return specs
 }
@@ -23,6 +23,9 @@
 package logcheck_test
 
 import (
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"golang.org/x/tools/go/analysis/analysistest"
@@ -39,3 +42,80 @@ func TestNamed(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, logcheck.Analyzer, "named")
 }
+
+func TestImportPaths(t *testing.T) {
+	flags := logcheck.Analyzer.Flags
+	defer flags.Set("importpaths", flags.Lookup("importpaths").DefValue)
+	if err := flags.Set("importpaths", "github.com/scionproto/scion/pkg/log"); err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, logcheck.Analyzer, "moved")
+}
+
+func TestSlog(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, logcheck.Analyzer, "stdslog")
+}
+
+func TestStructured(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, logcheck.Analyzer, "structured")
+}
+
+func TestFormat(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, logcheck.Analyzer, "format")
+}
+
+func TestSprint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, logcheck.Analyzer, "sprint")
+}
+
+func TestFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, logcheck.Analyzer, "fix")
+}
+
+// TestRefix checks that the fixed calls are not reported again.
+func TestRefix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, logcheck.Analyzer, "refix")
+
+	// Analyze the golden file without expectations. Any diagnostic fails the
+	// test.
+	golden, err := os.ReadFile(filepath.Join(testdata, "src/refix/refix.go.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed := regexp.MustCompile(`\s*// want .*`).ReplaceAll(golden, nil)
+	dir := t.TempDir()
+	for file, content := range map[string][]byte{
+		"src/refix/refix.go": fixed,
+		"src/github.com/scionproto/scion/go/lib/log/log.go": nil,
+	} {
+		if content == nil {
+			if content, err = os.ReadFile(filepath.Join(testdata, file)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.Run(t, dir, logcheck.Analyzer, "refix")
+}
+
+func TestWrapper(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, logcheck.Analyzer, "wrapperlib", "wrapperuse")
+}
+
+func TestBroken(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, logcheck.Analyzer, "broken")
+}
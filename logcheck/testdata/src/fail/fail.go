@@ -57,12 +57,12 @@ func validParity() {
 	log.Error("message", "key", value)
 	log.Crit("message", "key", value)
 
-	log.Trace("message", "key", value, "key", value)
-	log.Debug("message", "key", value, "key", value)
-	log.Info("message", "key", value, "key", value)
-	log.Warn("message", "key", value, "key", value)
-	log.Error("message", "key", value, "key", value)
-	log.Crit("message", "key", value, "key", value)
+	log.Trace("message", "key", value, "other", value)
+	log.Debug("message", "key", value, "other", value)
+	log.Info("message", "key", value, "other", value)
+	log.Warn("message", "key", value, "other", value)
+	log.Error("message", "key", value, "other", value)
+	log.Crit("message", "key", value, "other", value)
 }
 
 func validTypes() {
@@ -79,18 +79,34 @@ func invalidParity() {
 	log.Error("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
 	log.Crit("message", "key")  // want `context should be even: len=1 ctx=\["key"\]`
 
-	log.Trace("message", "key", value, "key") // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	log.Debug("message", "key", value, "key") // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	log.Info("message", "key", value, "key")  // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	log.Warn("message", "key", value, "key")  // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	log.Error("message", "key", value, "key") // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	log.Crit("message", "key", value, "key")  // want `context should be even: len=3 ctx=\["key",value,"key"\]`
+	log.Trace("message", "key", value, "other") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	log.Debug("message", "key", value, "other") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	log.Info("message", "key", value, "other")  // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	log.Warn("message", "key", value, "other")  // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	log.Error("message", "key", value, "other") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	log.Crit("message", "key", value, "other")  // want `context should be even: len=3 ctx=\["key",value,"other"\]`
 }
 
 func invalidType() {
 	log.Info("message", value, value) // want `key should be string: type="int" name="value"`
 }
 
+func duplicateKeys() {
+	log.Info("message", "id", value, "id", value)               // want `duplicate key: key="id"`
+	log.Info("message", untyped, value, "untyped_key", value)   // want `duplicate key: key="untyped_key"`
+	log.Info("message", typed, value, "a", value, typed, value) // want `duplicate key: key="typed_key"`
+	log.New("id", value, "id", value)                           // want `duplicate key: key="id"`
+	log.Info("message", "id", value, "other", "id")
+}
+
+func reservedKeys(logger log.Logger) {
+	log.Info("message", "msg", value)   // want `key is reserved: key="msg"`
+	log.Info("message", "lvl", value)   // want `key is reserved: key="lvl"`
+	logger.Info("message", "t", value)  // want `key is reserved: key="t"`
+	log.New("msg", value)               // want `key is reserved: key="msg"`
+	log.Info("message", "level", value) // not reserved by the log package.
+}
+
 func logger() {
 	logger := log.FromCtx(context.Background())
 	loggerN := log.New()
@@ -104,4 +120,76 @@ func logger() {
 	log.Root().Info("message", "key")                        // want `context should be even: len=1 ctx=\["key"\]`
 }
 
+func newContext(logger log.Logger) {
+	log.New("key", value)
+	log.New(untyped, value, typed, value)
+	logger.New("key", value).Info("message", "key", value)
+	log.Root().New("key", value).Info("message")
+
+	log.New("key")                                        // want `context should be even: len=1 ctx=\["key"\]`
+	log.New(value, "value")                               // want `key should be string: type="int" name="value"`
+	logger.New("key")                                     // want `context should be even: len=1 ctx=\["key"\]`
+	logger.New(value, "value")                            // want `key should be string: type="int" name="value"`
+	log.Root().New("key", value, "other").Info("message") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	log.Root().New("key", value).Info("message", "other") // want `context should be even: len=1 ctx=\["other"\]`
+}
+
+type server struct {
+	logger log.Logger
+}
+
+func (s *server) handle() {
+	s.logger.Info("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+}
+
+func param(logger log.Logger) {
+	logger.Info("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+}
+
+func newLogger() log.Logger {
+	return log.Root()
+}
+
+func loggerTypes() {
+	var logger log.Logger
+	logger.Info("message", "key")      // want `context should be even: len=1 ctx=\["key"\]`
+	newLogger().Info("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+
+	var custom customLogger
+	custom.Info("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+
+	var ptr ptrLogger
+	ptr.Info("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+
+	var other otherLogger
+	other.Info("message", "key")
+}
+
+// customLogger implements log.Logger.
+type customLogger struct {
+	log.Logger
+}
+
+// ptrLogger implements log.Logger with pointer receivers.
+type ptrLogger struct{}
+
+func (*ptrLogger) New(ctx ...interface{}) log.Logger    { return nil }
+func (*ptrLogger) Trace(msg string, ctx ...interface{}) {}
+func (*ptrLogger) Debug(msg string, ctx ...interface{}) {}
+func (*ptrLogger) Info(msg string, ctx ...interface{})  {}
+func (*ptrLogger) Warn(msg string, ctx ...interface{})  {}
+func (*ptrLogger) Error(msg string, ctx ...interface{}) {}
+func (*ptrLogger) Crit(msg string, ctx ...interface{})  {}
+
+// otherLogger has log methods, but does not implement log.Logger.
+type otherLogger struct{}
+
+func (otherLogger) Info(msg string, ctx ...interface{}) {}
+
+func ellipsis() {
+	ctx := []interface{}{"key", value}
+	ctx = append(ctx, "other")
+	log.Info("message", ctx...) // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+}
+
 type key string
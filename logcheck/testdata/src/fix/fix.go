@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package fix
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+type ia uint64
+
+func (i ia) String() string { return strconv.FormatUint(uint64(i), 10) }
+
+// name is a named string type, which log/slog does not accept as key.
+type name string
+
+const typed name = "typed"
+
+type server struct {
+	isdAS ia
+	count int
+}
+
+var value = 1
+
+func danglingKey(logger log.Logger) {
+	log.Info("message", "key")                    // want `context should be even: len=1 ctx=\["key"\]`
+	logger.Info("message", "key", value, "other") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+}
+
+func missingKey(s server) {
+	log.Info("message", value, value)               // want `key should be string: type="int" name="value"`
+	log.Info("message", s.count)                    // want `context should be even: len=1` `key should be string: type="int" name="s.count"`
+	log.Info("message", "key", value, value)        // want `context should be even: len=3` `key should be string: type="int" name="value"`
+	log.Info("message", "count", value, s.count, 1) // want `key should be string: type="int" name="s.count"`
+	log.Info("message", value, s.count, value)      // want `context should be even: len=3` `key should be string: type="int" name="value"` `key should be string: type="int" name="value"`
+	log.Info("message", value, "key", s.count)      // want `context should be even: len=3` `key should be string: type="int" name="value"` `key should be string: type="int" name="s.count"`
+	log.Info("message", s.count, "key", "other")    // want `context should be even: len=3` `key should be string: type="int" name="s.count"`
+}
+
+func stringerKey(s server, i ia) {
+	log.Info("message", i, value)       // want `key should be string: type="fix.ia" name="i"`
+	log.Info("message", s.isdAS, value) // want `key should be string: type="fix.ia" name="s.isdAS"`
+	log.Info("message", i+1, value)     // want `key should be string: type="fix.ia" name="i \+ 1"`
+}
+
+func namedStringKey() {
+	slog.Info("message", typed, value) // want `key should be string: type="fix.name" name="typed"`
+}
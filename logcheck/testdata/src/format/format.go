@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package format
+
+import (
+	"log/slog"
+
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+const formatMsg = "got %d paths"
+
+type server struct {
+	isdAS string
+	paths []int
+}
+
+func formatVerbs(s server, n int, logger log.Logger) {
+	log.Info("got %d paths", n)                     // want `message should not contain format verbs: verbs=\[%d\]`
+	log.Debug("registered %s at %v", s.isdAS, n)    // want `message should not contain format verbs: verbs=\[%s,%v\]`
+	logger.Warn("%s: failed", s.isdAS)              // want `message should not contain format verbs: verbs=\[%s\]`
+	slog.Info("paths=%d total=%d", len(s.paths), n) // want `message should not contain format verbs: verbs=\[%d,%d\]`
+	log.Info("count %d/%d", n, n)                   // want `message should not contain format verbs: verbs=\[%d,%d\]`
+	log.Info("peer: %s, retrying", s.isdAS)         // want `message should not contain format verbs: verbs=\[%s\]`
+	slog.Info("request level=%d", n)                // want `message should not contain format verbs: verbs=\[%d\]`
+}
+
+func noFix(n int, ctx []interface{}) {
+	log.Info(formatMsg, n)           // want `message should not contain format verbs: verbs=\[%d\]`
+	log.Info("got %d paths")         // want `message should not contain format verbs: verbs=\[%d\]`
+	log.Info("got %d paths", ctx...) // want `message should not contain format verbs: verbs=\[%d\]`
+	log.Info("got %[1]d paths", n)   // want `message should not contain format verbs: verbs=\[%\[1\]d\]`
+}
+
+func valid(n int) {
+	log.Info("100% done", "n", n)
+	log.Info("got 100%% of paths", "n", n)
+}
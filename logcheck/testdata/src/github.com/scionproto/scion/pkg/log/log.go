@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package log is a minimal stub of the moved scion log package for testing.
+package log
+
+import "context"
+
+// Logger describes the logger interface.
+type Logger interface {
+	New(ctx ...interface{}) Logger
+	Trace(msg string, ctx ...interface{})
+	Debug(msg string, ctx ...interface{})
+	Info(msg string, ctx ...interface{})
+	Warn(msg string, ctx ...interface{})
+	Error(msg string, ctx ...interface{})
+	Crit(msg string, ctx ...interface{})
+}
+
+type logger struct{}
+
+func (logger) New(ctx ...interface{}) Logger        { return logger{} }
+func (logger) Trace(msg string, ctx ...interface{}) {}
+func (logger) Debug(msg string, ctx ...interface{}) {}
+func (logger) Info(msg string, ctx ...interface{})  {}
+func (logger) Warn(msg string, ctx ...interface{})  {}
+func (logger) Error(msg string, ctx ...interface{}) {}
+func (logger) Crit(msg string, ctx ...interface{})  {}
+
+func New(ctx ...interface{}) Logger        { return logger{} }
+func Root() Logger                         { return logger{} }
+func FromCtx(ctx context.Context) Logger   { return logger{} }
+func Trace(msg string, ctx ...interface{}) {}
+func Debug(msg string, ctx ...interface{}) {}
+func Info(msg string, ctx ...interface{})  {}
+func Warn(msg string, ctx ...interface{})  {}
+func Error(msg string, ctx ...interface{}) {}
+func Crit(msg string, ctx ...interface{})  {}
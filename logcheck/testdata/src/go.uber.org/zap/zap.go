@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package zap is a minimal stub of the zap package for testing.
+package zap
+
+import "go.uber.org/zap/zapcore"
+
+// Field is an alias for zapcore.Field.
+type Field = zapcore.Field
+
+// Int constructs a field with the given key and value.
+func Int(key string, val int) Field { return Field{Key: key, Integer: int64(val)} }
+
+// Logger is a strongly typed logger.
+type Logger struct{}
+
+func (l *Logger) Info(msg string, fields ...Field) {}
+func (l *Logger) Sugar() *SugaredLogger            { return &SugaredLogger{} }
+
+// SugaredLogger is a loosely typed logger.
+type SugaredLogger struct{}
+
+func (s *SugaredLogger) With(args ...interface{}) *SugaredLogger         { return s }
+func (s *SugaredLogger) Debugw(msg string, keysAndValues ...interface{}) {}
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...interface{})  {}
+func (s *SugaredLogger) Warnw(msg string, keysAndValues ...interface{})  {}
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {}
+func (s *SugaredLogger) Fatalw(msg string, keysAndValues ...interface{}) {}
+func (s *SugaredLogger) Infof(template string, args ...interface{})      {}
+
+func (s *SugaredLogger) Logw(lvl zapcore.Level, msg string, keysAndValues ...interface{}) {}
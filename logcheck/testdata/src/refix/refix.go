@@ -0,0 +1,53 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package refix contains calls for which every diagnostic is resolved by
+// applying the suggested fixes.
+package refix
+
+import (
+	"strconv"
+
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+type ia uint64
+
+func (i ia) String() string { return strconv.FormatUint(uint64(i), 10) }
+
+type server struct {
+	isdAS ia
+	count int
+}
+
+var value = 1
+
+func fixes(s server, i ia) {
+	log.Info("message", "key")                   // want `context should be even`
+	log.Info("message", "key", value, "other")   // want `context should be even`
+	log.Info("message", s.count)                 // want `context should be even` `key should be string`
+	log.Info("message", "key", value, value)     // want `context should be even` `key should be string`
+	log.Info("message", value, "key", s.count)   // want `context should be even` `key should be string` `key should be string`
+	log.Info("message", s.count, "key", "other") // want `context should be even` `key should be string`
+	log.Info("message", i, value, s.isdAS, 1)    // want `key should be string` `key should be string`
+	log.Info("got %d paths", s.count)            // want `message should not contain format verbs`
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sprint
+
+import (
+	"fmt"
+
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+type service struct {
+	name string
+	addr string
+}
+
+func sprintf(svc service, addr string, logger log.Logger) {
+	log.Debug(fmt.Sprintf("registered %s at %v", svc.name, addr))     // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	logger.Info(fmt.Sprintf("registered %s", svc.name), "addr", addr) // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	log.Info(fmt.Sprintf("addr=%s", addr), "addr", svc.addr)          // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	log.Info(fmt.Sprintf("done"))                                     // want `message should be constant, use key/value context instead of fmt.Sprintf`
+}
+
+func sprint(svc service, addr string) {
+	log.Info(fmt.Sprint("registered ", svc.name, " at ", addr)) // want `message should be constant, use key/value context instead of fmt.Sprint`
+}
+
+func noFix(format string, args []interface{}, ctx []interface{}) {
+	log.Info(fmt.Sprintf(format, args...))     // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	log.Info(fmt.Sprintf("a %s %s", "b"))      // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	log.Info(fmt.Sprintf("a %s", "b"), ctx...) // want `message should be constant, use key/value context instead of fmt.Sprintf`
+}
+
+func valid(addr string) {
+	log.Info("registered", "addr", addr)
+	log.Info("message", "addr", fmt.Sprintf("%s:%d", addr, 80))
+	log.New("key", fmt.Sprint(addr))
+	fmt.Println(fmt.Sprintf("registered %s", addr))
+}
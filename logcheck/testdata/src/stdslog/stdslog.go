@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package stdslog
+
+import (
+	"context"
+	"log/slog"
+)
+
+const (
+	untyped     = "untyped_key"
+	typed   key = "typed_key"
+)
+
+var (
+	ctx   = context.Background()
+	value = 1
+	attr  = slog.Int("key", 1)
+)
+
+func valid(logger *slog.Logger) {
+	slog.Info("message")
+	slog.Info("message", "key", value)
+	slog.Info("message", untyped, value, string(typed), value)
+	slog.Info("message", attr)
+	slog.Info("message", attr, "key", value, attr)
+	slog.Info("message", "key", attr)
+	slog.InfoContext(ctx, "message", "key", value)
+	slog.Log(ctx, slog.LevelInfo, "message", "key", value)
+	slog.With("key", value)
+	slog.Group("group", "key", value, attr)
+
+	logger.Debug("message", "key", value, attr)
+	logger.With(attr, "key", value).Info("message")
+	logger.Log(ctx, slog.LevelWarn, "message", attr)
+	logger.LogAttrs(ctx, slog.LevelWarn, "message", attr)
+}
+
+func invalidParity(logger *slog.Logger) {
+	slog.Debug("message", "key")                               // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Info("message", "key")                                // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Warn("message", "key")                                // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Error("message", "key")                               // want `context should be even: len=1 ctx=\["key"\]`
+	slog.ErrorContext(ctx, "message", "key")                   // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Log(ctx, slog.LevelInfo, "message", "key")            // want `context should be even: len=1 ctx=\["key"\]`
+	slog.With("key")                                           // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Group("group", "key")                                 // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Info("message", attr, "key", value, "other")          // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	slog.Info("message", "key", value, attr, "other")          // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	logger.Info("message", "key")                              // want `context should be even: len=1 ctx=\["key"\]`
+	logger.InfoContext(ctx, "message", attr, "key")            // want `context should be even: len=1 ctx=\["key"\]`
+	logger.Log(ctx, slog.LevelInfo, "message", "key")          // want `context should be even: len=1 ctx=\["key"\]`
+	logger.With("key").Info("message")                         // want `context should be even: len=1 ctx=\["key"\]`
+	slog.Default().With("key", value).Warn("message", "other") // want `context should be even: len=1 ctx=\["other"\]`
+}
+
+func invalidType(logger *slog.Logger) {
+	slog.Info("message", value, value)         // want `key should be string: type="int" name="value"`
+	logger.Info("message", attr, value, value) // want `key should be string: type="int" name="value"`
+	slog.Info("message", typed, value)         // want `key should be string: type="stdslog.key" name="typed"`
+	logger.With(untyped, value, typed, value)  // want `key should be string: type="stdslog.key" name="typed"`
+}
+
+func reservedKeys(logger *slog.Logger) {
+	slog.Info("message", "time", value)  // want `key is reserved: key="time"`
+	slog.Info("message", "level", value) // want `key is reserved: key="level"`
+	logger.Warn("message", "msg", value) // want `key is reserved: key="msg"`
+	logger.With("source", value)         // want `key is reserved: key="source"`
+	slog.Info("message", "lvl", value)   // not reserved by log/slog.
+}
+
+type key string
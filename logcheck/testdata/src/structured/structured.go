@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package structured
+
+import (
+	"errors"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+)
+
+var (
+	errBase = errors.New("base")
+	value   = 1
+	field   = zap.Int("key", 1)
+)
+
+// key is a named string type, which the loggers do not accept as key.
+type key string
+
+const typed key = "typed"
+
+// customError implements error.
+type customError struct{}
+
+func (customError) Error() string { return "custom" }
+
+type marshaler struct{}
+
+func (marshaler) MarshalLog() any { return nil }
+
+var _ logr.Marshaler = marshaler{}
+
+func validZap(logger *zap.Logger, sugar *zap.SugaredLogger) {
+	sugar.Infow("message")
+	sugar.Infow("message", "key", value)
+	sugar.Infow("message", field, "key", value, field)
+	sugar.Logw(0, "message", "key", value)
+	sugar.With("key", value, field).Debugw("message")
+	sugar.Infof("%d %d %d", value, value, value)
+	logger.Info("message", field)
+	logger.Sugar().Warnw("message", "key", value)
+	// Errors in key position are logged without a key.
+	sugar.Errorw("failed", errBase)
+	sugar.Errorw("failed", errBase, "key", value)
+	sugar.With(customError{}).Infow("message", "key", errBase)
+}
+
+func invalidZap(logger *zap.Logger, sugar *zap.SugaredLogger) {
+	sugar.Debugw("message", "key")                   // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Infow("message", "key")                    // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Warnw("message", "key")                    // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Errorw("message", "key")                   // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Fatalw("message", "key")                   // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Logw(0, "message", "key")                  // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.With("key")                                // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Infow("message", field, "key")             // want `context should be even: len=1 ctx=\["key"\]`
+	logger.Sugar().Infow("message", "key")           // want `context should be even: len=1 ctx=\["key"\]`
+	sugar.Infow("message", value, value)             // want `key should be string: type="int" name="value"`
+	sugar.With(field, value, value).Infow("message") // want `key should be string: type="int" name="value"`
+	sugar.Infow("message", typed, value)             // want `key should be string: type="structured.key" name="typed"`
+	sugar.Errorw("failed", errBase, "key")           // want `context should be even: len=1 ctx=\["key"\]`
+}
+
+func reservedZap(sugar *zap.SugaredLogger) {
+	sugar.Infow("message", "ts", value) // want `key is reserved: key="ts"`
+	sugar.With("stacktrace", value)     // want `key is reserved: key="stacktrace"`
+	sugar.Infow("message", "lvl", value)
+}
+
+func validLogr(logger logr.Logger) {
+	logger.Info("message")
+	logger.Info("message", "key", value)
+	logger.Info("message", "key", marshaler{})
+	logger.Error(errBase, "message", "key", value)
+	logger.WithValues("key", value).Info("message")
+	logger.V(1).Info("message", "key", value)
+	logger.WithName("name").Error(errBase, "message")
+}
+
+func reservedLogr(logger logr.Logger) {
+	logger.Info("message", "error", value) // want `key is reserved: key="error"`
+	logger.WithValues("logger", value)     // want `key is reserved: key="logger"`
+	logger.Info("message", "ts", value)
+}
+
+func invalidLogr(logger logr.Logger) {
+	logger.Info("message", "key")                      // want `context should be even: len=1 ctx=\["key"\]`
+	logger.Error(errBase, "message", "key")            // want `context should be even: len=1 ctx=\["key"\]`
+	logger.WithValues("key")                           // want `context should be even: len=1 ctx=\["key"\]`
+	logger.V(1).Info("message", "key", value, "other") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	logger.Info("message", marshaler{}, value)         // want `key should be string: type="structured.marshaler" name="marshaler{}"`
+	logger.WithValues(typed, value)                    // want `key should be string: type="structured.key" name="typed"`
+	logger.Info("message", errBase, value)             // want `key should be string: type="error" name="errBase"`
+}
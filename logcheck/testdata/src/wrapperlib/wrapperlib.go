@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapperlib
+
+import "github.com/scionproto/scion/go/lib/log"
+
+// Server logs with a base context.
+type Server struct {
+	baseCtx []interface{}
+}
+
+func (s *Server) Logf(msg string, ctx ...interface{}) { // want Logf:`kvwrapper\(msg=0 start=1\)`
+	log.Info(msg, append(s.baseCtx, ctx...)...)
+}
+
+func Debug(ctx ...interface{}) { // want Debug:`kvwrapper\(msg=-1 start=0\)`
+	log.Debug("debug", ctx...)
+}
+
+func Indirect(level int, msg string, ctx ...interface{}) { // want Indirect:`kvwrapper\(msg=1 start=2\)`
+	forward(msg, ctx...)
+}
+
+func forward(msg string, ctx ...interface{}) { // want forward:`kvwrapper\(msg=0 start=1\)`
+	log.Root().Warn(msg, ctx...)
+}
+
+func NotForwarded(msg string, ctx ...interface{}) {
+	log.Info(msg, "len", len(ctx))
+}
+
+func NotVariadic(msg string, ctx []interface{}) {
+	log.Info(msg, ctx...)
+}
+
+func local() {
+	forward("message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+}
@@ -0,0 +1,39 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package wrapperuse
+
+import "wrapperlib"
+
+var value = 1
+
+func use(s *wrapperlib.Server) {
+	s.Logf("message", "key", value)
+	s.Logf("message", "key")                 // want `context should be even: len=1 ctx=\["key"\]`
+	s.Logf("message", value, value)          // want `key should be string: type="int" name="value"`
+	s.Logf("got %d paths", value)            // want `message should not contain format verbs`
+	wrapperlib.Debug("key")                  // want `context should be even: len=1 ctx=\["key"\]`
+	wrapperlib.Debug("msg", value)           // want `key is reserved: key="msg"`
+	wrapperlib.Indirect(1, "message", "key") // want `context should be even: len=1 ctx=\["key"\]`
+	wrapperlib.NotForwarded("message", "key")
+	wrapperlib.NotVariadic("message", []interface{}{"key"})
+}
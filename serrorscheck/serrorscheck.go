@@ -23,114 +23,58 @@
 package serrorscheck
 
 import (
-	"bytes"
-	"fmt"
-	"go/ast"
-	"go/printer"
-	"go/token"
-	"go/types"
-	"strings"
-
 	"golang.org/x/tools/go/analysis"
+
+	"github.com/oncilla/gochecks/kvcheck"
 )
 
-// Analyzer checks all calls on the serrors package.
-var Analyzer = &analysis.Analyzer{
-	Name:             "serrorscheck",
-	Doc:              "reports invalid serrors calls",
-	Run:              run,
-	RunDespiteErrors: true,
-}
+// serrorsPkg is the import path of the serrors package.
+const serrorsPkg = "github.com/scionproto/scion/go/lib/serrors"
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	for _, file := range pass.Files {
-		tgtPkg := findPkgName(file)
-		if tgtPkg == "" {
-			continue
-		}
-		ast.Inspect(file, func(n ast.Node) bool {
-			ce, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
-			se, ok := ce.Fun.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
-			pkg, ok := se.X.(*ast.Ident)
-			if !ok || pkg.Name != tgtPkg {
-				return true
-			}
-			var varargs []ast.Expr
-			switch se.Sel.Name {
-			case "New":
-				if len(ce.Args) < 2 {
-					return true
-				}
-				varargs = ce.Args[1:]
-			case "WithCtx":
-				if len(ce.Args) < 2 {
-					pass.Reportf(ce.Pos(), "should have context: expr=%q", render(pass.Fset, ce))
-					return true
-				}
-				varargs = ce.Args[1:]
-			case "Wrap", "WrapStr":
-				if len(ce.Args) < 3 {
-					return true
-				}
-				varargs = ce.Args[2:]
-			}
-			// We cannot check if varargs with ellipsis.
-			if ce.Ellipsis != token.NoPos {
-				return true
-			}
-			if len(varargs)%2 != 0 {
-				pass.Reportf(varargs[0].Pos(), "context should be even: len=%d ctx=%s expr=%q",
-					len(varargs), renderCtx(pass.Fset, varargs), render(pass.Fset, ce))
-			}
-			for i := 0; i < len(varargs); i += 2 {
-				lit := varargs[i]
-				if !isString(pass, lit) {
-					pass.Reportf(lit.Pos(), "key should be string: type=%q name=%q expr=%q",
-						pass.TypesInfo.TypeOf(lit), render(pass.Fset, lit), render(pass.Fset, ce))
-				}
-			}
-			return true
-		})
-	}
-	return nil, nil
-}
+// reservedKeys are the keys that serrors uses when rendering errors.
+var reservedKeys = []string{"msg", "cause"}
 
-func findPkgName(file *ast.File) string {
-	var tgtPkg string
-	for _, imp := range file.Imports {
-		if imp.Path.Value == `"github.com/scionproto/scion/go/lib/serrors"` {
-			tgtPkg = "serrors"
-			if imp.Name != nil {
-				tgtPkg = imp.Name.Name
-			}
-		}
-	}
-	return tgtPkg
-}
+// Analyzer checks all calls on the serrors package.
+var Analyzer = NewAnalyzer()
 
-func isString(pass *analysis.Pass, lit ast.Expr) bool {
-	t, ok := pass.TypesInfo.TypeOf(lit).Underlying().(*types.Basic)
-	return ok && t.Info()&types.IsString != 0
-}
+// factKey distinguishes the facts of the analyzer from the facts of other
+// analyzers built on kvcheck.
+type factKey struct{}
 
-func renderCtx(fset *token.FileSet, varargs []ast.Expr) string {
-	var p []string
-	for _, arg := range varargs {
-		p = append(p, render(fset, arg))
-	}
-	return fmt.Sprintf("[%s]", strings.Join(p, ","))
+// NewAnalyzer creates an instance of the analyzer with its own flags.
+func NewAnalyzer() *analysis.Analyzer {
+	return kvcheck.NewAnalyzer[factKey]("serrorscheck", "SERR",
+		"reports invalid serrors calls", specs)
 }
 
-func render(fset *token.FileSet, x interface{}) string {
-	var buf bytes.Buffer
-	if err := printer.Fprint(&buf, fset, x); err != nil {
-		panic(err)
-	}
-	return buf.String()
+var specs = []kvcheck.CallSpec{
+	{
+		ImportPath:   serrorsPkg,
+		Name:         "New",
+		Msg:          0,
+		Start:        1,
+		ReservedKeys: reservedKeys,
+	},
+	{
+		ImportPath:   serrorsPkg,
+		Name:         "WithCtx",
+		Msg:          kvcheck.NoMsg,
+		Start:        1,
+		RequireCtx:   true,
+		ReservedKeys: reservedKeys,
+	},
+	{
+		ImportPath:   serrorsPkg,
+		Name:         "Wrap",
+		Msg:          kvcheck.NoMsg,
+		Start:        2,
+		ReservedKeys: reservedKeys,
+	},
+	{
+		ImportPath:   serrorsPkg,
+		Name:         "WrapStr",
+		Msg:          0,
+		Start:        2,
+		ReservedKeys: reservedKeys,
+	},
 }
@@ -32,10 +32,51 @@ import (
 
 func Test(t *testing.T) {
 	testdata := analysistest.TestData()
-	analysistest.Run(t, testdata, serrorscheck.Analyzer, "fail")
+	resolved(t, analysistest.Run(t, testdata, serrorscheck.Analyzer, "fail"))
 }
 
 func TestNamed(t *testing.T) {
 	testdata := analysistest.TestData()
-	analysistest.Run(t, testdata, serrorscheck.Analyzer, "named")
+	resolved(t, analysistest.Run(t, testdata, serrorscheck.Analyzer, "named"))
+}
+
+func TestImportPaths(t *testing.T) {
+	flags := serrorscheck.Analyzer.Flags
+	defer flags.Set("importpaths", flags.Lookup("importpaths").DefValue)
+	if err := flags.Set("importpaths", "github.com/scionproto/scion/pkg/private/serrors"); err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	resolved(t, analysistest.Run(t, testdata, serrorscheck.Analyzer, "moved"))
+}
+
+func TestFormat(t *testing.T) {
+	testdata := analysistest.TestData()
+	resolved(t, analysistest.RunWithSuggestedFixes(t, testdata, serrorscheck.Analyzer, "format"))
+}
+
+func TestSprint(t *testing.T) {
+	testdata := analysistest.TestData()
+	resolved(t, analysistest.RunWithSuggestedFixes(t, testdata, serrorscheck.Analyzer, "sprint"))
+}
+
+func TestFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	resolved(t, analysistest.RunWithSuggestedFixes(t, testdata, serrorscheck.Analyzer, "fix"))
+}
+
+func TestWrapper(t *testing.T) {
+	testdata := analysistest.TestData()
+	resolved(t, analysistest.Run(t, testdata, serrorscheck.Analyzer, "wrapperlib", "wrapperuse"))
+}
+
+// resolved checks that the packages were type-checked without errors, i.e.,
+// that the calls resolve to the serrors stubs.
+func resolved(t *testing.T, results []*analysistest.Result) {
+	t.Helper()
+	for _, r := range results {
+		for _, err := range r.Pass.TypeErrors {
+			t.Errorf("%s: %v", r.Pass.Pkg.Path(), err)
+		}
+	}
 }
@@ -45,10 +45,10 @@ func validParity() {
 	serrors.Wrap(errWrap, errBase, "key", value)
 	serrors.WrapStr("wrap", errBase, "key", value)
 
-	serrors.New("some error", "key", value, "key", value)
-	serrors.WithCtx(errBase, "key", value, "key", value)
-	serrors.Wrap(errWrap, errBase, "key", value, "key", value)
-	serrors.WrapStr("wrap", errBase, "key", value, "key", value)
+	serrors.New("some error", "key", value, "other", value)
+	serrors.WithCtx(errBase, "key", value, "other", value)
+	serrors.Wrap(errWrap, errBase, "key", value, "other", value)
+	serrors.WrapStr("wrap", errBase, "key", value, "other", value)
 }
 
 func validTypes() {
@@ -63,10 +63,10 @@ func invalidParity() {
 	serrors.Wrap(errWrap, errBase, "key")   // want `context should be even: len=1 ctx=\["key"\]`
 	serrors.WrapStr("wrap", errBase, "key") // want `context should be even: len=1 ctx=\["key"\]`
 
-	serrors.New("some error", "key", value, "key")        // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	serrors.WithCtx(errBase, "key", value, "key")         // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	serrors.Wrap(errWrap, errBase, "key", value, "key")   // want `context should be even: len=3 ctx=\["key",value,"key"\]`
-	serrors.WrapStr("wrap", errBase, "key", value, "key") // want `context should be even: len=3 ctx=\["key",value,"key"\]`
+	serrors.New("some error", "key", value, "other")        // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	serrors.WithCtx(errBase, "key", value, "other")         // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	serrors.Wrap(errWrap, errBase, "key", value, "other")   // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+	serrors.WrapStr("wrap", errBase, "key", value, "other") // want `context should be even: len=3 ctx=\["key",value,"other"\]`
 }
 
 func invalidType() {
@@ -76,10 +76,30 @@ func invalidType() {
 	serrors.WrapStr("wrap", errBase, value, value) // want `key should be string: type="int" name="value"`
 }
 
+func duplicateKeys() {
+	serrors.New("some error", "ia", value, "ia", value)                    // want `duplicate key: key="ia"`
+	serrors.WithCtx(errBase, untyped, value, "untyped_key", value)         // want `duplicate key: key="untyped_key"`
+	serrors.Wrap(errWrap, errBase, typed, value, "a", value, typed, value) // want `duplicate key: key="typed_key"`
+	serrors.WrapStr("wrap", errBase, "ia", value, "other", "ia")
+}
+
+func reservedKeys() {
+	serrors.New("some error", "msg", value)                    // want `key is reserved: key="msg"`
+	serrors.WithCtx(errBase, "cause", value)                   // want `key is reserved: key="cause"`
+	serrors.Wrap(errWrap, errBase, "key", value, "msg", value) // want `key is reserved: key="msg"`
+	serrors.WrapStr("wrap", errBase, "lvl", value)
+}
+
 func noCtx() {
 	serrors.WithCtx(errBase) // want `should have context:`
 }
 
+func ellipsis() {
+	ctx := []interface{}{"key", value}
+	ctx = append(ctx, "other")
+	serrors.New("some error", ctx...) // want `context should be even: len=3 ctx=\["key",value,"other"\]`
+}
+
 type key string
 
 type multiKey key
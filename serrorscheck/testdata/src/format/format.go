@@ -0,0 +1,41 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package format
+
+import "github.com/scionproto/scion/go/lib/serrors"
+
+var errBase = serrors.New("base")
+
+func formatVerbs(ia string, n int) {
+	serrors.New("bad ia %s", ia)                      // want `message should not contain format verbs: verbs=\[%s\]`
+	serrors.WrapStr("parsing %q failed", errBase, ia) // want `message should not contain format verbs: verbs=\[%q\]`
+	serrors.New("%d paths, expected=%d", n, n)        // want `message should not contain format verbs: verbs=\[%d,%d\]`
+	serrors.WrapStr("wrapping cause=%v", errBase, ia) // want `message should not contain format verbs: verbs=\[%v\]`
+	serrors.New("bad ia %w", errBase)                 // want `message should not contain format verbs: verbs=\[%w\]`
+}
+
+func valid(ia string) {
+	serrors.New("bad ia", "ia", ia)
+	serrors.WrapStr("parsing failed", errBase, "ia", ia)
+	serrors.WithCtx(errBase, "ia", ia)
+}
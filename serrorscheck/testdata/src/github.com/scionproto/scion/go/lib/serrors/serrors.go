@@ -0,0 +1,39 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package serrors is a minimal stub of the serrors package for testing.
+package serrors
+
+// New creates a new error with the given message and context.
+func New(msg string, errCtx ...interface{}) error { return nil }
+
+// WithCtx returns an error that is the same as the given error but contains
+// the additional context.
+func WithCtx(err error, errCtx ...interface{}) error { return nil }
+
+// Wrap wraps the cause with the msg error and adds context to the resulting
+// error.
+func Wrap(msg, cause error, errCtx ...interface{}) error { return nil }
+
+// WrapStr wraps the cause with an error that has msg in the error message and
+// adds the additional context.
+func WrapStr(msg string, cause error, errCtx ...interface{}) error { return nil }
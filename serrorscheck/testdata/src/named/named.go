@@ -40,8 +40,8 @@ func fakeImportIgnored() {
 }
 
 func valid() {
-	errors.New("some error", "key", value, "key", value)
-	errors.WithCtx(errBase, "key", value, "key", value)
-	errors.Wrap(errWrap, errBase, "key", value, "key", value)
-	errors.WrapStr("wrap", errBase, "key", value, "key", value)
+	errors.New("some error", "key", value, "other", value)
+	errors.WithCtx(errBase, "key", value, "other", value)
+	errors.Wrap(errWrap, errBase, "key", value, "other", value)
+	errors.WrapStr("wrap", errBase, "key", value, "other", value)
 }
@@ -0,0 +1,42 @@
+// MIT License
+//
+// Copyright (c) 2020 Oncilla
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sprint
+
+import (
+	"fmt"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+var errBase = serrors.New("base")
+
+func sprintf(ia string, n int) {
+	serrors.New(fmt.Sprintf("bad ia %s", ia))                    // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	serrors.WrapStr(fmt.Sprintf("parsing %d paths", n), errBase) // want `message should be constant, use key/value context instead of fmt.Sprintf`
+	serrors.WrapStr(fmt.Sprint("parsing ", ia), errBase, "n", n) // want `message should be constant, use key/value context instead of fmt.Sprint`
+}
+
+func valid(ia string) {
+	serrors.New("bad ia", "ia", fmt.Sprint(ia))
+	serrors.WithCtx(errBase, "ia", fmt.Sprintf("%s", ia))
+}